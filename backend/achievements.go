@@ -0,0 +1,222 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"galycherrygame/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// achievementInterval is how often every player is checked for achievements
+// that no single event completes, such as skill levels
+const achievementInterval = time.Second
+
+// achievementDefinitions holds every achievement that can be earned, keyed by achievement ID
+var achievementDefinitions = map[uint]models.AchievementDefinition{
+	1: {ID: 1, Name: "First Blood", Description: "Defeat your first enemy",
+		Criteria: []models.AchievementCriterion{{Type: models.CriterionKills, Amount: 1}},
+		Reward:   models.AchievementReward{Gold: 25}},
+	2: {ID: 2, Name: "Goblin Slayer", Description: "Defeat 25 goblins",
+		Criteria: []models.AchievementCriterion{{Type: models.CriterionKills, Target: "Goblin", Amount: 25}},
+		Reward:   models.AchievementReward{Gold: 200, Title: "Goblin Bane"}},
+	3: {ID: 3, Name: "Monster Hunter", Description: "Defeat 100 enemies",
+		Criteria: []models.AchievementCriterion{{Type: models.CriterionKills, Amount: 100}},
+		Reward:   models.AchievementReward{Gold: 1000, Items: []models.InventoryItem{catalogItem(3, 5)}, Title: "Hunter"}},
+	4: {ID: 4, Name: "Apprentice Smith", Description: "Craft your first item",
+		Criteria: []models.AchievementCriterion{{Type: models.CriterionItemsCrafted, Amount: 1}},
+		Reward:   models.AchievementReward{Gold: 50}},
+	5: {ID: 5, Name: "Artisan", Description: "Craft 25 items",
+		Criteria: []models.AchievementCriterion{{Type: models.CriterionItemsCrafted, Amount: 25}},
+		Reward:   models.AchievementReward{Gold: 500, Title: "Artisan"}},
+	6: {ID: 6, Name: "Steel Yourself", Description: "Craft a Steel Sword",
+		Criteria: []models.AchievementCriterion{{Type: models.CriterionItemsCrafted, ItemID: 8, Amount: 1}},
+		Reward:   models.AchievementReward{Items: []models.InventoryItem{catalogItem(50, 5)}}},
+	7: {ID: 7, Name: "Seasoned Fighter", Description: "Reach combat level 10",
+		Criteria: []models.AchievementCriterion{{Type: models.CriterionSkillLevel, Target: models.SkillCombat, Amount: 10}},
+		Reward:   models.AchievementReward{Gold: 300, Title: "Warrior"}},
+	8: {ID: 8, Name: "Angler", Description: "Reach fishing level 10",
+		Criteria: []models.AchievementCriterion{{Type: models.CriterionSkillLevel, Target: models.SkillFishing, Amount: 10}},
+		Reward:   models.AchievementReward{Items: []models.InventoryItem{catalogItem(15, 20)}}},
+	9: {ID: 9, Name: "Prospector", Description: "Reach mining level 15",
+		Criteria: []models.AchievementCriterion{{Type: models.CriterionSkillLevel, Target: models.SkillMining, Amount: 15}},
+		Reward:   models.AchievementReward{Items: []models.InventoryItem{catalogItem(42, 1)}}},
+	10: {ID: 10, Name: "Jack of All Trades", Description: "Reach level 10 in fishing, cooking, mining and woodcutting",
+		Criteria: []models.AchievementCriterion{
+			{Type: models.CriterionSkillLevel, Target: models.SkillFishing, Amount: 10},
+			{Type: models.CriterionSkillLevel, Target: models.SkillCooking, Amount: 10},
+			{Type: models.CriterionSkillLevel, Target: models.SkillMining, Amount: 10},
+			{Type: models.CriterionSkillLevel, Target: models.SkillWoodcutting, Amount: 10},
+		},
+		Reward: models.AchievementReward{Gold: 1000, Title: "Jack of All Trades"}},
+	11: {ID: 11, Name: "Pocket Money", Description: "Earn 1,000 gold",
+		Criteria: []models.AchievementCriterion{{Type: models.CriterionGoldEarned, Amount: 1000}},
+		Reward:   models.AchievementReward{Items: []models.InventoryItem{catalogItem(21, 1)}}},
+	12: {ID: 12, Name: "Tycoon", Description: "Earn 10,000 gold",
+		Criteria: []models.AchievementCriterion{{Type: models.CriterionGoldEarned, Amount: 10000}},
+		Reward:   models.AchievementReward{Title: "Tycoon"}},
+	13: {ID: 13, Name: "Adventurer", Description: "Complete your first quest",
+		Criteria: []models.AchievementCriterion{{Type: models.CriterionQuestsCompleted, Amount: 1}},
+		Reward:   models.AchievementReward{Gold: 100}},
+}
+
+// playerStats holds the counters achievements are checked against, keyed by player ID
+var playerStats = map[uint]*models.PlayerStats{}
+
+// statsFor returns the player's stats, creating them on first use
+func statsFor(playerID uint) *models.PlayerStats {
+	stats, ok := playerStats[playerID]
+	if !ok {
+		stats = models.NewPlayerStats()
+		playerStats[playerID] = stats
+	}
+	return stats
+}
+
+// recordKill counts a defeated enemy towards the player's achievements and
+// slayer points, adding any achievements it completes to the combat log
+func recordKill(p *models.Player, enemy models.Enemy, combatLog []string) []string {
+	stats := statsFor(p.ID)
+	stats.Kills[enemy.Name]++
+	// Catalog mobs score their catalog level. Other enemies, such as dungeon
+	// bosses, are only ever built by the server.
+	level := enemy.Level
+	if known, ok := enemyCatalog[enemy.Name]; ok {
+		level = known.Level
+	}
+	stats.SlayerPoints += level
+	return describeAchievements(checkAchievements(p), combatLog)
+}
+
+// recordCrafted counts a crafted item towards the player's achievements
+func recordCrafted(p *models.Player, item models.InventoryItem) []models.Achievement {
+	statsFor(p.ID).ItemsCrafted[item.ID] += item.Quantity
+	return checkAchievements(p)
+}
+
+// recordGoldEarned counts gold the player received towards their achievements.
+// Gold from other players and from achievements themselves doesn't count, and
+// market sales are counted net of fees by settleMarketTrade.
+func recordGoldEarned(playerID uint, source string, amount int) {
+	switch source {
+	case models.SourceTrade, models.SourceAchievement, models.SourceMarketTrade:
+		return
+	}
+	addGoldEarned(playerID, amount)
+}
+
+// addGoldEarned adds to the gold the player has earned and checks the
+// achievements it counts towards
+func addGoldEarned(playerID uint, amount int) {
+	if amount <= 0 {
+		return
+	}
+	statsFor(playerID).GoldEarned += amount
+	if p, ok := players[playerID]; ok {
+		checkAchievements(p)
+	}
+}
+
+// checkAchievements completes every achievement the player now meets the
+// criteria for and grants its reward, returning the ones completed
+func checkAchievements(p *models.Player) []models.Achievement {
+	stats := statsFor(p.ID)
+
+	ids := make([]uint, 0, len(achievementDefinitions))
+	for id := range achievementDefinitions {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	completed := []models.Achievement{}
+	for _, id := range ids {
+		definition := achievementDefinitions[id]
+		if p.HasAchievement(id) || !definition.IsMet(p, stats) {
+			continue
+		}
+
+		achievement := models.Achievement{
+			PlayerID:      p.ID,
+			AchievementID: id,
+			Name:          definition.Name,
+			Description:   definition.Description,
+			CompletedAt:   time.Now(),
+			Reward:        definition.Reward.String(),
+		}
+		p.Achievements = append(p.Achievements, achievement)
+		grantAchievementReward(p, definition.Reward)
+		completed = append(completed, achievement)
+	}
+	return completed
+}
+
+// grantAchievementReward gives the player an achievement's gold, items and
+// title. Items that don't fit in the inventory are lost.
+func grantAchievementReward(p *models.Player, reward models.AchievementReward) {
+	if reward.Gold > 0 {
+		p.Gold += reward.Gold
+		recordGold(p.ID, models.SourceAchievement, reward.Gold)
+	}
+	for _, item := range reward.Items {
+		if !p.CanAddItem(item.ID) {
+			continue
+		}
+		p.AddItemToInventory(item)
+		recordItem(p.ID, models.SourceAchievement, item.ID, item.Quantity)
+	}
+	if reward.Title != "" {
+		p.Titles = append(p.Titles, reward.Title)
+	}
+}
+
+// describeAchievements adds a line for each completed achievement to a log
+func describeAchievements(achievements []models.Achievement, log []string) []string {
+	for _, achievement := range achievements {
+		log = append(log, fmt.Sprintf("Achievement unlocked: %s! You received %s.", achievement.Name, achievement.Reward))
+	}
+	return log
+}
+
+// achievementProgress is an incomplete achievement with the player's progress towards it
+type achievementProgress struct {
+	models.AchievementDefinition
+	Progress []models.CriterionProgress `json:"progress"`
+}
+
+// getPlayerAchievements returns the player's completed achievements and their
+// progress towards the rest
+func getPlayerAchievements(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+	checkAchievements(p)
+	stats := statsFor(p.ID)
+
+	inProgress := []achievementProgress{}
+	for id, definition := range achievementDefinitions {
+		if p.HasAchievement(id) {
+			continue
+		}
+		inProgress = append(inProgress, achievementProgress{
+			AchievementDefinition: definition,
+			Progress:              definition.Progress(p, stats),
+		})
+	}
+	sort.Slice(inProgress, func(i, j int) bool { return inProgress[i].ID < inProgress[j].ID })
+
+	completed := p.Achievements
+	if completed == nil {
+		completed = []models.Achievement{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"completed":  completed,
+		"inProgress": inProgress,
+		"titles":     p.Titles,
+		"stats":      stats,
+	})
+}
@@ -4,7 +4,10 @@ import (
 	"fmt"
 	"math/rand"
 	"net/http"
+	"sort"
 	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"galycherrygame/backend/models"
 	"galycherrygame/db"
@@ -17,13 +20,20 @@ func init() {
 }
 
 var player = models.Player{
+	ID:                1,
 	Name:              "Hero",
 	Health:            100,
 	MaxHealth:         100,
+	Stamina:           100,
+	MaxStamina:        100,
+	Location:          startingLocation,
+	Home:              startingLocation,
+	CombatStyle:       models.StyleMelee,
 	Level:             1,
 	Experience:        0,
 	ExperienceToLevel: 100,
 	Gold:              50,
+	SkillCap:          99,
 	Skills: models.PlayerSkills{
 		Combat:   1,
 		Fishing:  1,
@@ -31,16 +41,19 @@ var player = models.Player{
 		Farming:  1,
 		Crafting: 1,
 		Alchemy:  1,
+		// Gathering skills
+		Mining:      1,
+		Woodcutting: 1,
 	},
 	Inventory: models.PlayerInventory{
 		Materials: []models.InventoryItem{
-			{Name: "Iron Sword", Description: "A basic sword", Quantity: 1},
-			{Name: "Leather Armor", Description: "Basic armor", Quantity: 1},
+			{ID: 1, Name: "Iron Sword", Description: "A basic sword", Type: "weapon", Quantity: 1},
+			{ID: 2, Name: "Leather Armor", Description: "Basic armor", Type: "armor", Quantity: 1},
 		},
 	},
 }
 
-func calculateEnemyDamage(enemy Enemy, player *models.Player) int {
+func calculateEnemyDamage(enemy models.Enemy, player *models.Player) int {
 	baseDamage := rand.Intn(enemy.MaxDamage) + 1
 	damage := baseDamage - (player.Skills.Combat / 2)
 	if damage < 1 {
@@ -49,6 +62,17 @@ func calculateEnemyDamage(enemy Enemy, player *models.Player) int {
 	return damage
 }
 
+// errorMessage returns an error as a message for players. Error strings are
+// lowercase, so the first letter is capitalized.
+func errorMessage(err error) string {
+	message := err.Error()
+	if message == "" {
+		return message
+	}
+	first, size := utf8.DecodeRuneInString(message)
+	return string(unicode.ToUpper(first)) + message[size:]
+}
+
 func maximum(a, b int) int {
 	if a > b {
 		return a
@@ -78,39 +102,6 @@ type InventoryItem struct {
 	Quantity    int
 }
 
-type Enemy struct {
-	Name           string          `json:"name"`
-	Health         int             `json:"health"`
-	MaxHealth      int             `json:"maxHealth"`
-	Level          int             `json:"level"`
-	MaxDamage      int             `json:"maxDamage"`
-	Defense        int             `json:"defense"`
-	AttackSpeed    int             `json:"attackSpeed"`
-	SpecialAbility *SpecialAbility `json:"specialAbility,omitempty"`
-}
-
-type SpecialAbility struct {
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Cooldown    int    `json:"cooldown"`
-	Effect      string `json:"effect"`
-}
-
-type CraftingRecipe struct {
-	ID         int
-	Name       string
-	SkillLevel int
-	Materials  []CraftingMaterial
-	OutputItem InventoryItem
-}
-
-type CraftingMaterial struct {
-	ID       int
-	RecipeID int
-	ItemID   int
-	Quantity int
-}
-
 type AlchemyFormula struct {
 	ID           int
 	Name         string
@@ -134,7 +125,14 @@ type CraftingStation struct {
 }
 
 func SetupRoutes(r *gin.Engine) {
+	r.Use(lockGame())
+
 	r.GET("/player", getPlayer)
+	r.GET("/player/skills", getPlayerSkills)
+	r.GET("/player/achievements", getPlayerAchievements)
+	r.POST("/player/allocate", allocateSkillPoints)
+	r.POST("/player/respec", respecAttributes)
+	r.POST("/players", createPlayer)
 	r.POST("/player/attack", attackEnemy)
 	r.POST("/player/defend", defend)
 	r.POST("/player/use-item", useItem)
@@ -146,75 +144,144 @@ func SetupRoutes(r *gin.Engine) {
 	r.GET("/alchemy-formulas", getAlchemyFormulas)
 	r.GET("/crafting-stations", getCraftingStations)
 
+	r.GET("/fishing/spots", getFishingSpots)
+	r.GET("/fishing/spots/:id", getFishingSpot)
+	r.POST("/fishing/spots/:id/fish", startFishing)
+	r.POST("/fishing/collect", collectCatch)
+
+	r.GET("/cooking/ranges", getCookingRanges)
+	r.GET("/cooking/recipes", getCookingRecipes)
+	r.POST("/cook", cookFood)
+
+	r.GET("/farm/crops", getCrops)
+	r.GET("/farm/plots", getFarmPlots)
+	r.POST("/farm/plots/:id/plant", plantCrop)
+	r.POST("/farm/plots/:id/water", waterPlot)
+	r.POST("/farm/plots/:id/compost", compostPlot)
+	r.POST("/farm/plots/:id/cure", curePlot)
+	r.POST("/farm/plots/:id/harvest", harvestPlot)
+	r.POST("/farm/plots/:id/clear", clearPlot)
+
+	r.GET("/resources", getResourceNodes)
+	r.POST("/resources/:id/gather", startGatheringNode)
+	r.POST("/resources/collect", collectResource)
+
+	r.GET("/idle", getIdleQueue)
+	r.POST("/idle", queueIdleAction)
+	r.POST("/idle/:id/cancel", cancelIdleAction)
+	r.GET("/idle/away", getAwaySummary)
+
+	r.GET("/capes", getCapes)
+	r.GET("/capes/:id/eligibility", getCapeEligibility)
+	r.POST("/capes/:id/claim", claimCape)
+	r.POST("/capes/:id/equip", equipCape)
+	r.POST("/capes/unequip", unequipCape)
+
+	r.GET("/world/locations", getLocations)
+	r.GET("/world/locations/:id", getLocation)
+	r.GET("/world/travel", getTravel)
+	r.POST("/world/travel", startTravel)
+
+	r.GET("/player/combat-style", getCombatStyle)
+	r.POST("/player/combat-style", setCombatStyle)
+	r.POST("/player/equip-weapon", equipWeapon)
+	r.POST("/player/unequip-weapon", unequipWeapon)
+	r.GET("/player/deaths", getPlayerDeaths)
+	r.GET("/graves", getGravestones)
+	r.POST("/graves/:id/retrieve", retrieveGravestone)
+
+	r.GET("/inns", getInns)
+	r.POST("/inns/:id/rest", restAtInn)
+	r.POST("/player/cast-heal", castHeal)
+
+	r.GET("/npcs", getNPCs)
+	r.POST("/npcs/:id/talk", talkToNPC)
+
+	r.GET("/leaderboards", getLeaderboardCategories)
+	r.GET("/leaderboards/:category", getLeaderboard)
+	r.GET("/leaderboards/:category/me", getMyRank)
+
+	r.GET("/dungeons", getDungeons)
+	r.POST("/dungeons/:id/enter", enterDungeon)
+	r.GET("/dungeons/generated", previewGeneratedDungeon)
+	r.POST("/dungeons/generated/enter", enterGeneratedDungeon)
+	r.GET("/dungeons/run", getDungeonRun)
+	r.POST("/dungeons/run/attack", attackInDungeon)
+	r.POST("/dungeons/run/advance", advanceDungeon)
+	r.POST("/dungeons/run/flee", fleeDungeon)
+
+	r.GET("/trades", getTrades)
+	r.POST("/trades", proposeTrade)
+	r.GET("/trades/:id", getTrade)
+	r.POST("/trades/:id/offer", offerInTrade)
+	r.POST("/trades/:id/confirm", confirmTrade)
+	r.POST("/trades/:id/cancel", cancelTrade)
+
+	r.POST("/market/orders", placeOrder)
+	r.GET("/market/orders", getMyOrders)
+	r.POST("/market/orders/:id/cancel", cancelOrder)
+	r.GET("/market/:itemId", getMarket)
+
+	r.GET("/admin/economy", requireAdmin(), getEconomyReport)
+
 	r.GET("/enemies", getEnemies)
+	r.GET("/enemies/:id/drops", getEnemyDrops)
 	r.GET("/quests", getAvailableQuests)
 	r.GET("/shop", getShopItems)
+	r.GET("/shop/:id", getShop)
+	r.POST("/shop/:id/buy", buyFromShop)
+	r.POST("/shop/:id/sell", sellToShop)
+	r.POST("/shop/:id/repair", repairWeapon)
 }
 
 func craftItem(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
 	var request struct {
-		RecipeID uint `json:"recipeId"`
+		RecipeID uint `json:"recipeId" binding:"required"`
 	}
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
 		return
 	}
 
-	var recipe CraftingRecipe
-	if err := c.ShouldBindJSON(&recipe); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	recipe, ok := craftingRecipes[request.RecipeID]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
 		return
 	}
 
-	recipe = CraftingRecipe{
-		ID:         1,
-		Name:       "Iron Sword",
-		SkillLevel: 5,
-		Materials: []CraftingMaterial{
-			{ItemID: 1, Quantity: 2},
-			{ItemID: 2, Quantity: 1},
-		},
-		OutputItem: InventoryItem{
-			Name:        "Iron Sword",
-			Description: "A basic iron sword",
-			Quantity:    1,
-		},
-	}
-
-	if player.Skills.Crafting < recipe.SkillLevel {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": fmt.Sprintf("Crafting skill level %d required (current: %d)",
-				recipe.SkillLevel, player.Skills.Crafting),
-		})
+	if err := canCraft(p, recipe); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
 		return
 	}
 
-	player.Experience += 50
-
-	if player.Experience >= player.ExperienceToLevel {
-		player.Level++
-		player.MaxHealth += 20
-		player.Health = player.MaxHealth
-		player.Experience = 0
-		player.ExperienceToLevel = int(float64(player.ExperienceToLevel) * 1.5)
-	}
-
-	player.Skills.Crafting++
+	levelUp, achievements := craft(p, recipe)
 
 	c.JSON(http.StatusOK, gin.H{
-		"message":    fmt.Sprintf("Successfully crafted %s!", recipe.OutputItem.Name),
-		"player":     player,
-		"newItem":    recipe.OutputItem,
-		"experience": 50,
+		"message":      fmt.Sprintf("Successfully crafted %s!", recipe.OutputItem.Name),
+		"player":       p,
+		"newItem":      recipe.OutputItem,
+		"experience":   recipe.Experience,
+		"levelUp":      levelUp,
+		"achievements": achievements,
 	})
 }
 
 func brewPotion(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
 	var request struct {
 		FormulaID uint `json:"formulaId"`
 	}
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
 		return
 	}
 
@@ -233,55 +300,32 @@ func brewPotion(c *gin.Context) {
 		},
 	}
 
-	if player.Skills.Alchemy < formula.SkillLevel {
+	if p.Skills.Alchemy < formula.SkillLevel {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error": fmt.Sprintf("Alchemy skill level %d required (current: %d)",
-				formula.SkillLevel, player.Skills.Alchemy),
+				formula.SkillLevel, p.Skills.Alchemy),
 		})
 		return
 	}
 
-	player.Experience += 30
-
-	if player.Experience >= player.ExperienceToLevel {
-		player.Level++
-		player.MaxHealth += 20
-		player.Health = player.MaxHealth
-		player.Experience = 0
-		player.ExperienceToLevel = int(float64(player.ExperienceToLevel) * 1.5)
-	}
-
-	player.Skills.Alchemy++
+	levelUp := grantExperience(p, 30)
+	p.AddSkillExperience(models.SkillAlchemy, 30)
 
 	c.JSON(http.StatusOK, gin.H{
 		"message":    fmt.Sprintf("Successfully brewed %s!", formula.Name),
-		"player":     player,
+		"player":     p,
 		"newPotion":  formula.OutputPotion,
 		"experience": 30,
+		"levelUp":    levelUp,
 	})
 }
 
 func getCraftingRecipes(c *gin.Context) {
-	var recipes []models.CraftingRecipe
-	err := db.DB.Find(&recipes).Error
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch crafting recipes"})
-		return
-	}
-
-	for i := range recipes {
-		err = db.DB.Where("recipe_id = ?", recipes[i].ID).Find(&recipes[i].Materials).Error
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch recipe materials"})
-			return
-		}
-
-		err = db.DB.Where("recipe_id = ?", recipes[i].ID).First(&recipes[i].OutputItem).Error
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to fetch output item"})
-			return
-		}
+	recipes := make([]models.CraftingRecipe, 0, len(craftingRecipes))
+	for _, recipe := range craftingRecipes {
+		recipes = append(recipes, recipe)
 	}
+	sort.Slice(recipes, func(i, j int) bool { return recipes[i].ID < recipes[j].ID })
 
 	c.JSON(http.StatusOK, recipes)
 }
@@ -322,53 +366,148 @@ func getCraftingStations(c *gin.Context) {
 }
 
 func getPlayer(c *gin.Context) {
-	c.JSON(http.StatusOK, player)
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+	updateRegeneration(p, time.Now())
+	c.JSON(http.StatusOK, p)
+}
+
+func getPlayerSkills(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, p.SkillProgress())
 }
 
+// enemyRequest names the catalog enemy the player is fighting
+type enemyRequest struct {
+	Name string `json:"name" binding:"required"`
+}
+
+// attackEnemy strikes the enemy the player is fighting. The enemy's stats
+// always come from the catalog and its health is tracked between attacks.
 func attackEnemy(c *gin.Context) {
-	var enemy Enemy
-	if err := c.ShouldBindJSON(&enemy); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	p, ok := currentPlayer(c)
+	if !ok {
 		return
 	}
 
-	combatLog := []string{}
+	var request enemyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	if _, ok := enemyCatalog[request.Name]; !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Enemy not found"})
+		return
+	}
+	if err := canFight(p, request.Name); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+	if err := canAttack(p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	enemy := currentFight(p, request.Name)
+	markCombat(p, time.Now())
+	combatLog, defeated := strikeEnemy(p, enemy, []string{})
+
+	if defeated {
+		delete(fights, p.ID)
+		var drops []models.LootDrop
+		combatLog, drops = rewardKill(p, *enemy, combatLog)
+
+		c.JSON(http.StatusOK, gin.H{
+			"player":    p,
+			"drops":     drops,
+			"combatLog": combatLog,
+		})
+		return
+	}
+
+	combatLog = enemyRetaliates(p, *enemy, combatLog)
+	combatLog, died := checkPlayerDeath(p, enemy.Name, combatLog)
+
+	c.JSON(http.StatusOK, gin.H{
+		"player":    p,
+		"enemy":     enemy,
+		"combatLog": combatLog,
+		"died":      died,
+	})
+}
+
+// rewardKill grants the experience, gold and loot for defeating an enemy
+func rewardKill(p *models.Player, enemy models.Enemy, combatLog []string) ([]string, []models.LootDrop) {
+	combatLog = grantCombatExperience(p, enemy, combatLog)
 
-	playerDamage := player.CalculateAttackDamage("physical")
-	effectiveDamage := playerDamage - enemy.Defense
+	goldEarned := enemy.Level * 10
+	p.Gold += goldEarned
+	recordGold(p.ID, models.SourceMobDrop, goldEarned)
+	combatLog = append(combatLog, fmt.Sprintf("You gained %d gold!", goldEarned))
+
+	drops := rollLoot(enemy.Name)
+	for _, drop := range drops {
+		p.AddItemToInventory(drop.Item)
+		recordItem(p.ID, models.SourceMobDrop, drop.Item.ID, drop.Item.Quantity)
+	}
+	return describeDrops(drops, combatLog), drops
+}
+
+// strikeEnemy applies one player attack in their combat style to the enemy,
+// using up any ammunition the style needs, and reports whether it was defeated.
+// Damage after the enemy's defense against the style is scaled by the combat
+// triangle and the enemy's weaknesses.
+func strikeEnemy(p *models.Player, enemy *models.Enemy, combatLog []string) ([]string, bool) {
+	style := p.Style()
+	damageType := models.DamageType(style)
+	playerDamage := p.CalculateAttackDamage(damageType) + useAmmo(p)
+	effectiveDamage := int(float64(playerDamage-enemy.DefenseAgainst(damageType)) * enemy.DamageMultiplier(style))
 	if effectiveDamage < 1 {
 		effectiveDamage = 1
 	}
+	combatLog = describeMatchup(style, *enemy, combatLog)
 	enemy.Health = maximum(0, enemy.Health-effectiveDamage)
 	combatLog = append(combatLog, fmt.Sprintf("You dealt %d damage to %s!", effectiveDamage, enemy.Name))
+	if p.WearWeapon() {
+		combatLog = append(combatLog, fmt.Sprintf("Your %s broke! Have it repaired at a smithy.", p.EquippedWeapon.Name))
+	}
 
 	if enemy.Health <= 0 {
 		combatLog = append(combatLog, fmt.Sprintf("You defeated %s!", enemy.Name))
+		return combatLog, true
+	}
+	return combatLog, false
+}
 
-		expEarned := player.CalculateExperienceGain(enemy.Level)
-		goldEarned := enemy.Level * 10
-		player.Experience += expEarned
-		player.Gold += goldEarned
-		combatLog = append(combatLog, fmt.Sprintf("You gained %d experience and %d gold!", expEarned, goldEarned))
-
-		if player.Experience >= player.ExperienceToLevel {
-			player.Level++
-			player.MaxHealth += 20
-			player.Health = player.MaxHealth
-			player.Experience = 0
-			player.ExperienceToLevel = int(float64(player.ExperienceToLevel) * 1.5)
-			combatLog = append(combatLog, "Level Up! Your max health has increased!")
-		}
-
-		c.JSON(http.StatusOK, gin.H{
-			"player":    player,
-			"combatLog": combatLog,
-		})
-		return
+// describeMatchup adds a line to the combat log when the player's style is
+// strong or weak against the enemy
+func describeMatchup(style string, enemy models.Enemy, combatLog []string) []string {
+	damageType := models.DamageType(style)
+	if enemy.IsWeakTo(damageType) {
+		combatLog = append(combatLog, fmt.Sprintf("%s is weak to %s attacks!", enemy.Name, style))
+	} else if enemy.DefenseAgainst(damageType) > enemy.Defense {
+		combatLog = append(combatLog, fmt.Sprintf("%s resists %s attacks.", enemy.Name, style))
 	}
+	switch {
+	case models.Beats(style, enemy.Style):
+		combatLog = append(combatLog, fmt.Sprintf("Your %s has the advantage over %s's %s.", style, enemy.Name, enemy.Style))
+	case models.Beats(enemy.Style, style):
+		combatLog = append(combatLog, fmt.Sprintf("%s's %s has the advantage over your %s.", enemy.Name, enemy.Style, style))
+	}
+	return combatLog
+}
 
-	enemyDamage := calculateEnemyDamage(enemy, &player)
-	player.TakeDamage(enemyDamage)
+// enemyRetaliates resolves the enemy's counterattack, including its special
+// ability. The combat triangle scales its damage as it does the player's.
+func enemyRetaliates(p *models.Player, enemy models.Enemy, combatLog []string) []string {
+	enemyDamage := maximum(1, int(float64(calculateEnemyDamage(enemy, p))*models.TriangleMultiplier(enemy.Style, p.Style())))
+	p.TakeDamage(enemyDamage)
 	combatLog = append(combatLog, fmt.Sprintf("%s dealt %d damage to you!", enemy.Name, enemyDamage))
 
 	if enemy.SpecialAbility != nil && enemy.Health <= int(float64(enemy.MaxHealth)*0.3) {
@@ -376,118 +515,235 @@ func attackEnemy(c *gin.Context) {
 			enemy.Name, enemy.SpecialAbility.Name, enemy.SpecialAbility.Effect))
 
 		enemyDamage = int(float64(enemyDamage) * 2)
-		player.TakeDamage(enemyDamage)
+		p.TakeDamage(enemyDamage)
 		combatLog = append(combatLog, fmt.Sprintf("%s deals an additional %d damage!", enemy.Name, enemyDamage))
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"player":    player,
-		"enemy":     enemy,
-		"combatLog": combatLog,
-	})
+	return combatLog
+}
+
+// grantCombatExperience awards the experience for defeating an enemy and levels the player up
+func grantCombatExperience(p *models.Player, enemy models.Enemy, combatLog []string) []string {
+	expEarned := p.CalculateExperienceGain(enemy.Level)
+	levelUp := grantExperience(p, expEarned)
+	combatLog = append(combatLog, fmt.Sprintf("You gained %d experience!", expEarned))
+
+	if levels := p.AddSkillExperience(models.SkillCombat, expEarned); levels > 0 {
+		combatLog = append(combatLog, fmt.Sprintf("Your combat level is now %d!", p.Skills.Combat))
+	}
+
+	if message, ok := describeLevelUp(levelUp); ok {
+		combatLog = append(combatLog, message)
+	}
+
+	return recordKill(p, enemy, combatLog)
 }
 
 func defend(c *gin.Context) {
-	var enemy Enemy
-	if err := c.ShouldBindJSON(&enemy); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	var request enemyRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
 		return
 	}
 
+	if _, ok := enemyCatalog[request.Name]; !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Enemy not found"})
+		return
+	}
+	if err := canFight(p, request.Name); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	enemy := currentFight(p, request.Name)
+
 	combatLog := []string{}
+	markCombat(p, time.Now())
 
-	defenseBonus := player.CalculateDefense() * 2
-	enemyDamage := calculateEnemyDamage(enemy, &player)
+	defenseBonus := p.CalculateDefense() * 2
+	enemyDamage := calculateEnemyDamage(*enemy, p)
 	effectiveDamage := maximum(0, enemyDamage-defenseBonus)
 	if effectiveDamage < 1 {
 		effectiveDamage = 1
 	}
-	player.TakeDamage(effectiveDamage)
+	p.TakeDamage(effectiveDamage)
 	combatLog = append(combatLog, fmt.Sprintf("You defended against %s's attack!", enemy.Name))
 	combatLog = append(combatLog, fmt.Sprintf("You took %d damage!", effectiveDamage))
+	combatLog, died := checkPlayerDeath(p, enemy.Name, combatLog)
 
 	c.JSON(http.StatusOK, gin.H{
-		"player":    player,
+		"player":    p,
 		"enemy":     enemy,
 		"combatLog": combatLog,
+		"died":      died,
 	})
 }
 
+// useItem consumes one of a consumable, named by ID or by name, applying its healing
 func useItem(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
 	var request struct {
-		Item string `json:"item"`
+		ItemID uint   `json:"itemId"`
+		Item   string `json:"item"`
 	}
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
 		return
 	}
 
-	c.JSON(http.StatusOK, player)
+	var item models.InventoryItem
+	found := false
+	for _, invItem := range p.Inventory.Materials {
+		if (request.ItemID != 0 && invItem.ID == request.ItemID) || (request.ItemID == 0 && invItem.Name == request.Item) {
+			item, found = invItem, true
+			break
+		}
+	}
+	if !found {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You do not have that item"})
+		return
+	}
+	if item.Type != "consumable" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s cannot be used", item.Name)})
+		return
+	}
+
+	heals := item.Stats.Healing > 0 || item.Stats.HealingOverTime > 0
+	if heals && p.Health >= p.MaxHealth {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You are already at full health"})
+		return
+	}
+
+	p.RemoveItemFromInventory(item.ID, 1)
+	healed := p.Heal(item.Stats.Healing)
+	message := fmt.Sprintf("You used %s and restored %d health", item.Name, healed)
+	if item.Stats.HealingOverTime > 0 {
+		now := time.Now()
+		p.ApplyHealingOverTime(item.Stats.HealingOverTime, item.Stats.HealingDuration, now)
+		updateRegeneration(p, now)
+		message = fmt.Sprintf("You used %s and will restore %d health every second for %d seconds",
+			item.Name, item.Stats.HealingOverTime, item.Stats.HealingDuration)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+		"player":  p,
+	})
 }
 
 func acceptQuest(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
 	var request struct {
 		Quest string `json:"quest"`
 	}
 	if err := c.ShouldBindJSON(&request); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
 		return
 	}
 
-	c.JSON(http.StatusOK, player)
+	questID := uint(0)
+	for i, quest := range availableQuests {
+		if quest == request.Quest {
+			questID = uint(i + 1)
+		}
+	}
+	if questID == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Quest not found"})
+		return
+	}
+	if err := atLocation(p, questLocations[request.Quest]); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+	if err := startQuest(p, questID); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+// enemyMatchups is an enemy with how each combat style fares against it
+type enemyMatchups struct {
+	models.Enemy
+	Matchups []models.StyleMatchup `json:"matchups"`
 }
 
+// getEnemies returns every enemy with its defenses, weaknesses and combat style matchups
 func getEnemies(c *gin.Context) {
-	enemies := map[string]Enemy{
-		"Goblin": {
-			Name:        "Goblin",
-			Health:      50,
-			MaxHealth:   50,
-			Level:       1,
-			MaxDamage:   5,
-			Defense:     2,
-			AttackSpeed: 2,
-		},
-		"Wolf": {
-			Name:        "Wolf",
-			Health:      75,
-			MaxHealth:   75,
-			Level:       2,
-			MaxDamage:   8,
-			Defense:     3,
-			AttackSpeed: 3,
-			SpecialAbility: &SpecialAbility{
-				Name:        "Pack Tactics",
-				Description: "Increases damage when fighting with allies",
-				Cooldown:    3,
-				Effect:      "Deals 50% more damage for 2 turns",
-			},
-		},
-		"Orc": {
-			Name:        "Orc",
-			Health:      100,
-			MaxHealth:   100,
-			Level:       3,
-			MaxDamage:   12,
-			Defense:     5,
-			AttackSpeed: 1,
-			SpecialAbility: &SpecialAbility{
-				Name:        "Berserker Rage",
-				Description: "Increases attack power when health is low",
-				Cooldown:    5,
-				Effect:      "Deals double damage when below 30% health",
-			},
-		},
+	enemies := make(map[string]enemyMatchups, len(enemyCatalog))
+	for name, enemy := range enemyCatalog {
+		enemies[name] = enemyMatchups{Enemy: enemy, Matchups: enemy.Matchups()}
 	}
 	c.JSON(http.StatusOK, enemies)
 }
 
-func getAvailableQuests(c *gin.Context) {
-	quests := []string{"Goblin Slayer", "Wolf Hunter"}
-	c.JSON(http.StatusOK, quests)
+// enemyCatalog holds every mob that can be fought, keyed by name
+var enemyCatalog = map[string]models.Enemy{
+	"Goblin": {
+		Name:        "Goblin",
+		Health:      50,
+		MaxHealth:   50,
+		Level:       1,
+		MaxDamage:   5,
+		Defense:     2,
+		AttackSpeed: 2,
+		Style:       models.StyleRanged,
+		Defenses:    map[string]int{"physical": 1},
+	},
+	"Wolf": {
+		Name:        "Wolf",
+		Health:      75,
+		MaxHealth:   75,
+		Level:       2,
+		MaxDamage:   8,
+		Defense:     3,
+		AttackSpeed: 3,
+		Style:       models.StyleMelee,
+		Defenses:    map[string]int{"ranged": 8},
+		SpecialAbility: &models.SpecialAbility{
+			Name:        "Pack Tactics",
+			Description: "Increases damage when fighting with allies",
+			Cooldown:    3,
+			Effect:      "Deals 50% more damage for 2 turns",
+		},
+	},
+	"Orc": {
+		Name:        "Orc",
+		Health:      100,
+		MaxHealth:   100,
+		Level:       3,
+		MaxDamage:   12,
+		Defense:     5,
+		AttackSpeed: 1,
+		Style:       models.StyleMelee,
+		Defenses:    map[string]int{"physical": 7, "magic": 1},
+		Weaknesses:  []string{"magic"},
+		SpecialAbility: &models.SpecialAbility{
+			Name:        "Berserker Rage",
+			Description: "Increases attack power when health is low",
+			Cooldown:    5,
+			Effect:      "Deals double damage when below 30% health",
+		},
+	},
 }
 
-func getShopItems(c *gin.Context) {
-	items := []string{"Iron Sword", "Leather Armor"}
-	c.JSON(http.StatusOK, items)
+// availableQuests lists every quest in the game
+var availableQuests = []string{"Goblin Slayer", "Wolf Hunter"}
+
+func getAvailableQuests(c *gin.Context) {
+	c.JSON(http.StatusOK, availableQuests)
 }
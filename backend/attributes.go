@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+
+	"galycherrygame/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respecCostPerLevel is the gold charged per character level to reset attributes
+const respecCostPerLevel = 50
+
+// allocateSkillPoints spends the player's unspent skill points on attributes
+func allocateSkillPoints(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	var allocation models.AttributeAllocation
+	if err := c.ShouldBindJSON(&allocation); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	if err := p.AllocateSkillPoints(allocation, progression); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Allocated %d skill points", allocation.Total()),
+		"player":  p,
+		"stats":   p.DerivedStats(),
+	})
+}
+
+// respecAttributes refunds every allocated skill point for a gold fee that scales with level
+func respecAttributes(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	if p.Strength+p.Dexterity+p.Magic == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "No skill points have been allocated"})
+		return
+	}
+
+	cost := respecCostPerLevel * p.Level
+	if p.Gold < cost {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Insufficient gold: %d required (current: %d)", cost, p.Gold),
+		})
+		return
+	}
+
+	p.Gold -= cost
+	recordGold(p.ID, models.SourceRespec, -cost)
+	refunded := p.ResetAttributes(progression)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Refunded %d skill points for %d gold", refunded, cost),
+		"player":  p,
+		"stats":   p.DerivedStats(),
+	})
+}
@@ -0,0 +1,239 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"galycherrygame/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// slayerTopTier is how many of each enemy must be defeated to reach the top slayer tier
+const slayerTopTier = 100
+
+// capes holds every milestone cape, keyed by cape ID
+var capes = map[uint]models.Cape{
+	1: {ID: 1, Name: "Mini Max Cape", Description: "For reaching level 50 in every skill", ItemID: 54, Cost: 25000,
+		Requirements: []models.CapeRequirement{{Type: models.RequireAllSkills, Level: 50}}},
+	2: {ID: 2, Name: "Max Cape", Description: "For mastering every skill and finishing every quest", ItemID: 55, Cost: 99000,
+		Requirements: []models.CapeRequirement{
+			{Type: models.RequireAllSkills, Level: 99},
+			{Type: models.RequireAllQuests},
+		}},
+	3: {ID: 3, Name: "No Life Cape", Description: "For doing absolutely everything there is to do", ItemID: 56, Cost: 250000,
+		Requirements: []models.CapeRequirement{
+			{Type: models.RequireAllSkills, Level: 99},
+			{Type: models.RequireAllQuests},
+			{Type: models.RequireAllAchievements},
+			{Type: models.RequireAllSlayerTiers, Level: slayerTopTier},
+		}},
+}
+
+func getCapes(c *gin.Context) {
+	list := make([]models.Cape, 0, len(capes))
+	for _, cape := range capes {
+		list = append(list, cape)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	c.JSON(http.StatusOK, list)
+}
+
+// getCapeEligibility explains which of a cape's requirements the player meets and what is still missing
+func getCapeEligibility(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+	cape, ok := findCape(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, capeEligibility(p, cape))
+}
+
+// claimCape buys a cape for a player who meets all of its requirements
+func claimCape(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+	cape, ok := findCape(c)
+	if !ok {
+		return
+	}
+
+	eligibility := capeEligibility(p, cape)
+	if !eligibility.Eligible {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":       fmt.Sprintf("You do not meet the requirements for the %s", cape.Name),
+			"eligibility": eligibility,
+		})
+		return
+	}
+	if p.ItemQuantity(cape.ItemID) > 0 || (p.EquippedCape != nil && p.EquippedCape.ID == cape.ItemID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("You already own the %s", cape.Name)})
+		return
+	}
+	if p.Gold < cape.Cost {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("The %s costs %d gold (you have %d)", cape.Name, cape.Cost, p.Gold),
+		})
+		return
+	}
+	if !p.CanAddItem(cape.ItemID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Not enough inventory space"})
+		return
+	}
+
+	p.Gold -= cape.Cost
+	recordGold(p.ID, models.SourceCape, -cape.Cost)
+	p.AddItemToInventory(catalogItem(cape.ItemID, 1))
+	recordItem(p.ID, models.SourceCape, cape.ItemID, 1)
+	recordCapeClaim(p, cape, time.Now())
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("You claimed the %s!", cape.Name),
+		"player":  p,
+	})
+}
+
+// equipCape wears a cape from the player's inventory, swapping out any cape already worn
+func equipCape(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+	cape, ok := findCape(c)
+	if !ok {
+		return
+	}
+
+	if p.ItemQuantity(cape.ItemID) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("You do not have a %s to wear", cape.Name)})
+		return
+	}
+
+	p.EquipCape(itemCatalog[cape.ItemID])
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("You put on the %s", cape.Name),
+		"player":  p,
+		"stats":   p.DerivedStats(),
+	})
+}
+
+// unequipCape takes off the player's cape and returns it to their inventory
+func unequipCape(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	if p.EquippedCape == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You are not wearing a cape"})
+		return
+	}
+	if !p.CanAddItem(p.EquippedCape.ID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Not enough inventory space"})
+		return
+	}
+
+	name := p.EquippedCape.Name
+	p.UnequipCape()
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("You take off the %s", name),
+		"player":  p,
+		"stats":   p.DerivedStats(),
+	})
+}
+
+// capeEligibility checks each of a cape's requirements against the player
+func capeEligibility(p *models.Player, cape models.Cape) models.CapeEligibility {
+	eligibility := models.CapeEligibility{Cape: cape, Eligible: true}
+	for _, requirement := range cape.Requirements {
+		missing := missingForRequirement(p, requirement)
+		status := models.RequirementStatus{
+			CapeRequirement: requirement,
+			Met:             len(missing) == 0,
+			Missing:         missing,
+		}
+		if !status.Met {
+			eligibility.Eligible = false
+		}
+		eligibility.Requirements = append(eligibility.Requirements, status)
+	}
+	return eligibility
+}
+
+// missingForRequirement lists what the player still needs to meet a cape requirement
+func missingForRequirement(p *models.Player, requirement models.CapeRequirement) []string {
+	missing := []string{}
+	switch requirement.Type {
+	case models.RequireAllSkills:
+		for _, skill := range p.SkillProgress() {
+			if skill.Level < requirement.Level {
+				missing = append(missing, fmt.Sprintf("%s level %d/%d", skill.Skill, skill.Level, requirement.Level))
+			}
+		}
+
+	case models.RequireAllQuests:
+		completed := map[uint]bool{}
+		for _, quest := range p.CompletedQuests {
+			if quest.Status == "completed" {
+				completed[quest.QuestID] = true
+			}
+		}
+		for i, quest := range availableQuests {
+			if !completed[uint(i+1)] {
+				missing = append(missing, fmt.Sprintf("Quest: %s", quest))
+			}
+		}
+
+	case models.RequireAllAchievements:
+		ids := make([]uint, 0, len(achievementDefinitions))
+		for id := range achievementDefinitions {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+		for _, id := range ids {
+			if !p.HasAchievement(id) {
+				missing = append(missing, fmt.Sprintf("Achievement: %s", achievementDefinitions[id].Name))
+			}
+		}
+
+	case models.RequireAllSlayerTiers:
+		names := make([]string, 0, len(enemyCatalog))
+		for name := range enemyCatalog {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		stats := statsFor(p.ID)
+		for _, name := range names {
+			if kills := stats.Kills[name]; kills < requirement.Level {
+				missing = append(missing, fmt.Sprintf("%s kills %d/%d", name, kills, requirement.Level))
+			}
+		}
+	}
+	return missing
+}
+
+// findCape looks up the cape named by the :id route parameter, writing an error response if it doesn't exist
+func findCape(c *gin.Context) (models.Cape, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid cape ID"})
+		return models.Cape{}, false
+	}
+
+	cape, ok := capes[uint(id)]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cape not found"})
+		return models.Cape{}, false
+	}
+	return cape, true
+}
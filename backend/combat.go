@@ -0,0 +1,205 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"galycherrygame/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ammunition holds every item used up by ranged and magic attacks, keyed by item ID
+var ammunition = map[uint]models.Ammo{
+	60: {ItemID: 60, Style: models.StyleRanged, Damage: 2},
+	61: {ItemID: 61, Style: models.StyleRanged, Damage: 5},
+	62: {ItemID: 62, Style: models.StyleMagic, Damage: 3},
+	63: {ItemID: 63, Style: models.StyleMagic, Damage: 8},
+}
+
+// fights holds the enemy each player is fighting outside of dungeons, keyed by player ID
+var fights = map[uint]*models.Enemy{}
+
+// currentFight returns the enemy the player is fighting, starting a fresh
+// fight against a copy from the catalog if they aren't already fighting one
+// by that name
+func currentFight(p *models.Player, name string) *models.Enemy {
+	if enemy, ok := fights[p.ID]; ok && enemy.Name == name {
+		return enemy
+	}
+	enemy := enemyCatalog[name]
+	fights[p.ID] = &enemy
+	return &enemy
+}
+
+// bestAmmo returns the strongest ammunition the player carries for a combat style
+func bestAmmo(p *models.Player, style string) (models.Ammo, bool) {
+	var best models.Ammo
+	found := false
+	for _, ammo := range ammunition {
+		if ammo.Style != style || p.ItemQuantity(ammo.ItemID) == 0 {
+			continue
+		}
+		if !found || ammo.Damage > best.Damage || (ammo.Damage == best.Damage && ammo.ItemID < best.ItemID) {
+			best, found = ammo, true
+		}
+	}
+	return best, found
+}
+
+// needsAmmo reports whether attacks in a combat style use up ammunition
+func needsAmmo(style string) bool {
+	return style == models.StyleRanged || style == models.StyleMagic
+}
+
+// canAttack checks the player has the ammunition their combat style needs
+func canAttack(p *models.Player) error {
+	style := p.Style()
+	if !needsAmmo(style) {
+		return nil
+	}
+	if _, ok := bestAmmo(p, style); !ok {
+		if style == models.StyleRanged {
+			return fmt.Errorf("you have no arrows left")
+		}
+		return fmt.Errorf("you have no runes left")
+	}
+	return nil
+}
+
+// useAmmo uses up one of the player's strongest ammunition for their combat
+// style and returns the damage it adds
+func useAmmo(p *models.Player) int {
+	style := p.Style()
+	if !needsAmmo(style) {
+		return 0
+	}
+	ammo, ok := bestAmmo(p, style)
+	if !ok {
+		return 0
+	}
+	p.RemoveItemFromInventory(ammo.ItemID, 1)
+	recordItem(p.ID, models.SourceAmmo, ammo.ItemID, -1)
+	return ammo.Damage
+}
+
+// getCombatStyle returns the player's combat style and the styles their weapon allows
+func getCombatStyle(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"style":     p.Style(),
+		"available": p.AvailableStyles(),
+		"weapon":    p.EquippedWeapon,
+	})
+}
+
+// setCombatStyle picks the combat style for the player's next encounter.
+// The style can't change mid-fight.
+func setCombatStyle(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Style string `json:"style" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	if inCombat(p, time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot change combat style while in combat"})
+		return
+	}
+	if err := p.SetCombatStyle(request.Style); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("You will fight with %s", p.Style()),
+		"player":  p,
+	})
+}
+
+// equipWeapon wields a weapon from the player's inventory, switching to its
+// combat style
+func equipWeapon(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		ItemID uint `json:"itemId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	var weapon models.InventoryItem
+	found := false
+	for _, item := range p.Inventory.Materials {
+		if item.ID == request.ItemID {
+			weapon, found = item, true
+			break
+		}
+	}
+	if !found {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You do not have that item"})
+		return
+	}
+	if weapon.Type != "weapon" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s is not a weapon", weapon.Name)})
+		return
+	}
+	if inCombat(p, time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot change weapons while in combat"})
+		return
+	}
+
+	p.EquipWeapon(weapon)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("You wield the %s", weapon.Name),
+		"player":  p,
+		"stats":   p.DerivedStats(),
+	})
+}
+
+// unequipWeapon puts the player's weapon back in their inventory
+func unequipWeapon(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	if p.EquippedWeapon == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You are not wielding a weapon"})
+		return
+	}
+	if inCombat(p, time.Now()) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot change weapons while in combat"})
+		return
+	}
+	if !p.CanAddItem(p.EquippedWeapon.ID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Not enough inventory space"})
+		return
+	}
+
+	name := p.EquippedWeapon.Name
+	p.UnequipWeapon()
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("You put away the %s", name),
+		"player":  p,
+		"stats":   p.DerivedStats(),
+	})
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"sort"
+	"time"
+
+	"galycherrygame/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cookingRanges holds the stations food can be cooked on, keyed by station ID
+var cookingRanges = map[uint]models.CraftingStation{
+	1: {ID: 1, Name: "Village Hearth", Description: "A crackling open fire", Type: models.StationCookingRange, SkillLevel: 1, Location: "Cherry Village"},
+	2: {ID: 2, Name: "Ironforge Range", Description: "A hot iron range that cooks evenly", Type: models.StationCookingRange, SkillLevel: 30, Location: "Ironforge"},
+}
+
+// cookingRecipes holds every dish that can be cooked, keyed by recipe ID
+var cookingRecipes = map[uint]models.CookingRecipe{
+	1: {ID: 1, Name: "Shrimp", Level: 1, Experience: 30, RawItemID: 16, CookedItemID: 22, BurntItemID: 27, BurnChance: 0.5, StopBurnLevel: 34},
+	2: {ID: 2, Name: "Trout", Level: 15, Experience: 70, RawItemID: 17, CookedItemID: 23, BurntItemID: 27, BurnChance: 0.5, StopBurnLevel: 50},
+	3: {ID: 3, Name: "Salmon", Level: 25, Experience: 90, RawItemID: 18, CookedItemID: 24, BurntItemID: 27, BurnChance: 0.5, StopBurnLevel: 58},
+	4: {ID: 4, Name: "Lobster", Level: 40, Experience: 120, RawItemID: 19, CookedItemID: 25, BurntItemID: 27, BurnChance: 0.55, StopBurnLevel: 74},
+	5: {ID: 5, Name: "Swordfish", Level: 45, Experience: 140, RawItemID: 20, CookedItemID: 26, BurntItemID: 27, BurnChance: 0.55, StopBurnLevel: 86},
+	6: {ID: 6, Name: "Baked Potato", Level: 5, Experience: 40, RawItemID: 32, CookedItemID: 39, BurntItemID: 27, BurnChance: 0.45, StopBurnLevel: 40},
+	7: {ID: 7, Name: "Cabbage Stew", Level: 20, Experience: 80, RawItemID: 33, CookedItemID: 40, BurntItemID: 27, BurnChance: 0.5, StopBurnLevel: 55},
+}
+
+func getCookingRanges(c *gin.Context) {
+	c.JSON(http.StatusOK, cookingRanges)
+}
+
+// getCookingRecipes lists every recipe with the player's current chance of burning it
+func getCookingRecipes(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	type recipeInfo struct {
+		models.CookingRecipe
+		CurrentBurnChance float64 `json:"currentBurnChance"`
+	}
+	recipes := []recipeInfo{}
+	for _, recipe := range cookingRecipes {
+		recipes = append(recipes, recipeInfo{recipe, recipe.BurnChanceAt(p.Skills.Cooking)})
+	}
+	sort.Slice(recipes, func(i, j int) bool { return recipes[i].ID < recipes[j].ID })
+	c.JSON(http.StatusOK, recipes)
+}
+
+// cookFood cooks raw ingredients on a cooking range, burning some of them
+// depending on the player's cooking level
+func cookFood(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		RecipeID  uint `json:"recipeId" binding:"required"`
+		StationID uint `json:"stationId" binding:"required"`
+		Quantity  int  `json:"quantity" binding:"min=0"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+	if request.Quantity == 0 {
+		request.Quantity = 1
+	}
+
+	recipe, ok := cookingRecipes[request.RecipeID]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Recipe not found"})
+		return
+	}
+	station, ok := cookingRanges[request.StationID]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cooking range not found"})
+		return
+	}
+
+	if err := canCook(p, recipe, station, request.Quantity); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	cooked, burnt := cook(p, recipe, request.Quantity, rng)
+
+	experience := cooked * recipe.Experience
+	message := fmt.Sprintf("You cooked %d %s and burnt %d on the %s", cooked, recipe.Name, burnt, station.Name)
+	if levels := p.AddSkillExperience(models.SkillCooking, experience); levels > 0 {
+		message += fmt.Sprintf(". Your cooking level is now %d!", p.Skills.Cooking)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    message,
+		"player":     p,
+		"cooked":     cooked,
+		"burnt":      burnt,
+		"experience": experience,
+	})
+}
+
+// canCook checks the player is at a station and can cook a quantity of a recipe on it
+func canCook(p *models.Player, recipe models.CookingRecipe, station models.CraftingStation, quantity int) error {
+	if err := atLocation(p, station.Location); err != nil {
+		return err
+	}
+	if p.Skills.Cooking < recipe.Level || p.Skills.Cooking < station.SkillLevel {
+		return fmt.Errorf("cooking level %d required (current: %d)",
+			maximum(recipe.Level, station.SkillLevel), p.Skills.Cooking)
+	}
+
+	raw := itemCatalog[recipe.RawItemID]
+	if p.ItemQuantity(raw.ID) < quantity {
+		return fmt.Errorf("you do not have %d %s to cook", quantity, raw.Name)
+	}
+	if !p.CanAddItems([]models.InventoryItem{catalogItem(recipe.CookedItemID, 1), catalogItem(recipe.BurntItemID, 1)}) {
+		return fmt.Errorf("not enough inventory space")
+	}
+	return nil
+}
+
+// cook turns a quantity of raw ingredients into food, returning how many were cooked and burnt
+func cook(p *models.Player, recipe models.CookingRecipe, quantity int, rng *rand.Rand) (cooked, burnt int) {
+	for i := 0; i < quantity; i++ {
+		if recipe.Burns(rng, p.Skills.Cooking) {
+			burnt++
+		} else {
+			cooked++
+		}
+	}
+
+	p.RemoveItemFromInventory(recipe.RawItemID, quantity)
+	if cooked > 0 {
+		p.AddItemToInventory(catalogItem(recipe.CookedItemID, cooked))
+	}
+	if burnt > 0 {
+		p.AddItemToInventory(catalogItem(recipe.BurntItemID, burnt))
+	}
+	return cooked, burnt
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+
+	"galycherrygame/backend/models"
+)
+
+// craftingRecipes holds everything that can be crafted from gathered ores and logs, keyed by recipe ID
+var craftingRecipes = map[uint]models.CraftingRecipe{
+	1: {ID: 1, Name: "Bronze Pickaxe", Description: "Forge a pickaxe from copper and tin", SkillLevel: 1, Experience: 25,
+		Materials:  []models.RecipeMaterial{{ItemID: 47, Quantity: 2}, {ItemID: 48, Quantity: 1}, {ItemID: 51, Quantity: 1}},
+		OutputItem: catalogItem(41, 1)},
+	2: {ID: 2, Name: "Bronze Axe", Description: "Forge an axe from copper and tin", SkillLevel: 1, Experience: 25,
+		Materials:  []models.RecipeMaterial{{ItemID: 47, Quantity: 2}, {ItemID: 48, Quantity: 1}, {ItemID: 51, Quantity: 1}},
+		OutputItem: catalogItem(44, 1)},
+	3: {ID: 3, Name: "Iron Sword", Description: "A basic iron sword", SkillLevel: 5, Experience: 50,
+		Materials:  []models.RecipeMaterial{{ItemID: 49, Quantity: 3}, {ItemID: 51, Quantity: 1}},
+		OutputItem: catalogItem(1, 1)},
+	4: {ID: 4, Name: "Iron Pickaxe", Description: "A pickaxe that mines faster than bronze", SkillLevel: 10, Experience: 60,
+		Materials:  []models.RecipeMaterial{{ItemID: 49, Quantity: 3}, {ItemID: 52, Quantity: 1}},
+		OutputItem: catalogItem(42, 1)},
+	5: {ID: 5, Name: "Iron Axe", Description: "An axe that chops faster than bronze", SkillLevel: 10, Experience: 60,
+		Materials:  []models.RecipeMaterial{{ItemID: 49, Quantity: 3}, {ItemID: 52, Quantity: 1}},
+		OutputItem: catalogItem(45, 1)},
+	6: {ID: 6, Name: "Steel Sword", Description: "A well balanced steel blade", SkillLevel: 20, Experience: 100,
+		Materials:  []models.RecipeMaterial{{ItemID: 49, Quantity: 3}, {ItemID: 50, Quantity: 2}, {ItemID: 52, Quantity: 1}},
+		OutputItem: catalogItem(8, 1)},
+	7: {ID: 7, Name: "Steel Pickaxe", Description: "A pickaxe that bites through rock", SkillLevel: 25, Experience: 120,
+		Materials:  []models.RecipeMaterial{{ItemID: 49, Quantity: 3}, {ItemID: 50, Quantity: 3}, {ItemID: 53, Quantity: 1}},
+		OutputItem: catalogItem(43, 1)},
+	8: {ID: 8, Name: "Steel Axe", Description: "An axe that fells trees in a few swings", SkillLevel: 25, Experience: 120,
+		Materials:  []models.RecipeMaterial{{ItemID: 49, Quantity: 3}, {ItemID: 50, Quantity: 3}, {ItemID: 53, Quantity: 1}},
+		OutputItem: catalogItem(46, 1)},
+	9: {ID: 9, Name: "Chainmail", Description: "Interlocking rings of steel", SkillLevel: 30, Experience: 150,
+		Materials:  []models.RecipeMaterial{{ItemID: 49, Quantity: 5}, {ItemID: 50, Quantity: 4}},
+		OutputItem: catalogItem(9, 1)},
+}
+
+// canCraft checks the player has the level, materials and inventory space for a recipe
+func canCraft(p *models.Player, recipe models.CraftingRecipe) error {
+	if p.Skills.Crafting < recipe.SkillLevel {
+		return fmt.Errorf("crafting skill level %d required (current: %d)", recipe.SkillLevel, p.Skills.Crafting)
+	}
+	if !p.HasMaterials(recipe.Materials) {
+		return fmt.Errorf("you do not have the materials to craft %s", recipe.Name)
+	}
+	if !p.CanAddItem(recipe.OutputItem.ID) {
+		return fmt.Errorf("not enough inventory space")
+	}
+	return nil
+}
+
+// craft uses up a recipe's materials to make its item, awarding character and
+// crafting experience and returning any achievements it completes
+func craft(p *models.Player, recipe models.CraftingRecipe) (models.LevelUp, []models.Achievement) {
+	p.RemoveMaterials(recipe.Materials)
+	p.AddItemToInventory(recipe.OutputItem)
+
+	levelUp := grantExperience(p, recipe.Experience)
+	p.AddSkillExperience(models.SkillCrafting, recipe.Experience)
+	return levelUp, recordCrafted(p, recipe.OutputItem)
+}
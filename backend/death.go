@@ -0,0 +1,231 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"galycherrygame/backend/models"
+	"galycherrygame/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// graveInterval is how often crumbled gravestones are cleared away
+const graveInterval = 10 * time.Second
+
+// deathPenalty is what players lose when they die
+var deathPenalty = models.DefaultDeathConfig
+
+// loadDeathConfig replaces the death penalty with the one in a JSON file.
+// Fields missing from the file keep their default values.
+func loadDeathConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	config := models.DefaultDeathConfig
+	if err := json.Unmarshal(data, &config); err != nil {
+		return err
+	}
+	if err := config.Validate(); err != nil {
+		return fmt.Errorf("invalid death config: %w", err)
+	}
+
+	deathPenalty = config
+	return nil
+}
+
+// gravestones holds the items dropped by dead players, keyed by gravestone ID
+var gravestones = map[uint]*models.Gravestone{}
+
+var nextGravestoneID uint = 1
+
+// deaths holds every death of each player, keyed by player ID
+var deaths = map[uint][]models.DeathRecord{}
+
+// killPlayer applies the death penalty to a player who has run out of health
+// and respawns them at home, adding what happened to the log. Any fight,
+// dungeon run, gathering, idle action or conversation in progress ends.
+func killPlayer(p *models.Player, cause string, log []string) []string {
+	now := time.Now()
+	location := p.Location
+	log = append(log, fmt.Sprintf("You were killed by %s!", cause))
+
+	if run, ok := dungeonRuns[p.ID]; ok && run.Status == models.RunActive {
+		run.End(models.RunDead)
+	}
+	delete(gatheringActions, p.ID)
+	delete(idleQueues, p.ID)
+	delete(conversations, p.ID)
+	delete(travels, p.ID)
+	delete(lastCombat, p.ID)
+	delete(fights, p.ID)
+
+	goldLost := p.Gold * deathPenalty.GoldLossPercent / 100
+	if goldLost > 0 {
+		p.Gold -= goldLost
+		recordGold(p.ID, models.SourceDeath, -goldLost)
+		log = append(log, fmt.Sprintf("You lost %d gold.", goldLost))
+	}
+
+	dropped := p.DropUnprotected(deathPenalty)
+	if len(dropped) > 0 {
+		for _, item := range dropped {
+			recordItem(p.ID, models.SourceDeath, item.ID, -item.Quantity)
+		}
+		grave := &models.Gravestone{
+			ID:        nextGravestoneID,
+			PlayerID:  p.ID,
+			Location:  location,
+			Items:     dropped,
+			DiedAt:    now,
+			ExpiresAt: now.Add(deathPenalty.GraveDuration()),
+		}
+		nextGravestoneID++
+		gravestones[grave.ID] = grave
+		log = append(log, fmt.Sprintf("You dropped %d items in a gravestone in %s. Retrieve them within %d minutes.",
+			len(dropped), location, deathPenalty.GraveMinutes))
+	}
+
+	statsFor(p.ID).Deaths++
+	saveDeathRecord(models.DeathRecord{
+		PlayerID:     p.ID,
+		Cause:        cause,
+		Location:     location,
+		GoldLost:     goldLost,
+		ItemsDropped: len(dropped),
+		DiedAt:       now,
+	})
+
+	p.Respawn(p.Home, deathPenalty.RespawnHealthPercent)
+	savePlayerLocation(p)
+	return append(log, fmt.Sprintf("You wake up in %s with %d health.", p.Home, p.Health))
+}
+
+// saveDeathRecord adds a death to the player's history and the death_records table
+func saveDeathRecord(record models.DeathRecord) {
+	if db.DB != nil {
+		if err := db.DB.Create(&record).Error; err != nil {
+			log.Printf("Failed to save the death of player %d: %v", record.PlayerID, err)
+		}
+	}
+	deaths[record.PlayerID] = append(deaths[record.PlayerID], record)
+}
+
+// loadDeathRecords reads every player's death history back from the death_records table
+func loadDeathRecords() error {
+	if db.DB == nil {
+		return nil
+	}
+
+	var records []models.DeathRecord
+	if err := db.DB.Order("died_at").Find(&records).Error; err != nil {
+		return err
+	}
+	for _, record := range records {
+		deaths[record.PlayerID] = append(deaths[record.PlayerID], record)
+	}
+	return nil
+}
+
+// checkPlayerDeath kills the player if they have run out of health,
+// reporting whether they died
+func checkPlayerDeath(p *models.Player, cause string, log []string) ([]string, bool) {
+	if p.Health > 0 {
+		return log, false
+	}
+	return killPlayer(p, cause, log), true
+}
+
+// clearExpiredGravestones removes gravestones that have crumbled, losing their items for good
+func clearExpiredGravestones(now time.Time) {
+	for id, grave := range gravestones {
+		if grave.HasExpired(now) {
+			delete(gravestones, id)
+		}
+	}
+}
+
+// getPlayerDeaths returns the player's deaths, most recent first
+func getPlayerDeaths(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	records := make([]models.DeathRecord, len(deaths[p.ID]))
+	copy(records, deaths[p.ID])
+	sort.Slice(records, func(i, j int) bool { return records[i].DiedAt.After(records[j].DiedAt) })
+
+	c.JSON(http.StatusOK, gin.H{
+		"total":  len(records),
+		"deaths": records,
+	})
+}
+
+// getGravestones lists the player's gravestones that haven't crumbled yet
+func getGravestones(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	clearExpiredGravestones(now)
+	list := []*models.Gravestone{}
+	for _, grave := range gravestones {
+		if grave.PlayerID == p.ID {
+			list = append(list, grave)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	c.JSON(http.StatusOK, list)
+}
+
+// retrieveGravestone returns a gravestone's items to the player, who must be
+// standing where they died
+func retrieveGravestone(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid gravestone ID"})
+		return
+	}
+	clearExpiredGravestones(time.Now())
+	grave, ok := gravestones[uint(id)]
+	if !ok || grave.PlayerID != p.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Gravestone not found"})
+		return
+	}
+
+	if err := atLocation(p, grave.Location); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+	if !p.CanAddItems(grave.Items) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Not enough inventory space"})
+		return
+	}
+
+	for _, item := range grave.Items {
+		p.AddItemToInventory(item)
+		recordItem(p.ID, models.SourceDeath, item.ID, item.Quantity)
+	}
+	delete(gravestones, grave.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("You recovered %d items from your gravestone", len(grave.Items)),
+		"player":  p,
+	})
+}
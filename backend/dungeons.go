@@ -0,0 +1,397 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"galycherrygame/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// dungeonCatalog holds the hand-authored dungeons, keyed by ID
+var dungeonCatalog = map[uint]models.Dungeon{
+	1: {
+		ID:               1,
+		Name:             "Goblin Warren",
+		Description:      "A cramped network of tunnels overrun by goblins",
+		RecommendedLevel: 1,
+		Rooms: []models.DungeonRoom{
+			{Type: models.RoomCombat, Description: "A goblin scout blocks the tunnel", Enemy: catalogEnemy("Goblin")},
+			{Type: models.RoomTrap, Description: "A tripwire releases a volley of darts", TrapDamage: 10},
+			{Type: models.RoomTreasure, Description: "A pile of stolen coins", Gold: 25, Chest: "Dungeon Chest"},
+			{Type: models.RoomRest, Description: "An abandoned campfire still gives off warmth", HealAmount: 30},
+			{Type: models.RoomBoss, Description: "The Goblin Chief rises from his throne of junk", Enemy: &models.Enemy{
+				Name:        "Goblin Chief",
+				Health:      90,
+				MaxHealth:   90,
+				Level:       3,
+				MaxDamage:   8,
+				Defense:     3,
+				AttackSpeed: 2,
+				Style:       models.StyleRanged,
+				Defenses:    map[string]int{"physical": 1},
+			}},
+		},
+	},
+	2: {
+		ID:               2,
+		Name:             "Howling Caves",
+		Description:      "Wolves and worse lurk in the dark",
+		RecommendedLevel: 3,
+		Rooms: []models.DungeonRoom{
+			{Type: models.RoomCombat, Description: "A wolf stalks you from the shadows", Enemy: catalogEnemy("Wolf")},
+			{Type: models.RoomCombat, Description: "Another wolf answers the howl", Enemy: catalogEnemy("Wolf")},
+			{Type: models.RoomRest, Description: "A dry alcove safe from the wind", HealAmount: 40},
+			{Type: models.RoomTrap, Description: "The floor gives way beneath you", TrapDamage: 20},
+			{Type: models.RoomTreasure, Description: "The remains of an unlucky adventurer", Gold: 60, Chest: "Dungeon Chest"},
+			{Type: models.RoomBoss, Description: "An orc warlord keeps the wolves as pets", Enemy: &models.Enemy{
+				Name:        "Orc Warlord",
+				Health:      160,
+				MaxHealth:   160,
+				Level:       5,
+				MaxDamage:   14,
+				Defense:     6,
+				AttackSpeed: 1,
+				Style:       models.StyleMelee,
+				Defenses:    map[string]int{"physical": 9, "magic": 2},
+				Weaknesses:  []string{"magic"},
+				SpecialAbility: &models.SpecialAbility{
+					Name:        "Berserker Rage",
+					Description: "Increases attack power when health is low",
+					Cooldown:    5,
+					Effect:      "Deals double damage when below 30% health",
+				},
+			}},
+		},
+	},
+}
+
+// dungeonRuns holds each player's current or most recent dungeon run, keyed by player ID
+var dungeonRuns = map[uint]*models.DungeonRun{}
+
+// catalogEnemy returns a copy of the named enemy from the enemy catalog
+func catalogEnemy(name string) *models.Enemy {
+	enemy := enemyCatalog[name]
+	return &enemy
+}
+
+func getDungeons(c *gin.Context) {
+	c.JSON(http.StatusOK, dungeonCatalog)
+}
+
+func enterDungeon(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dungeon ID"})
+		return
+	}
+
+	dungeon, ok := dungeonCatalog[uint(id)]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Dungeon not found"})
+		return
+	}
+
+	startDungeonRun(c, dungeon)
+}
+
+// startDungeonRun begins a run through the dungeon for the player and resolves the first room
+func startDungeonRun(c *gin.Context, dungeon models.Dungeon) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	if run, ok := dungeonRuns[p.ID]; ok && run.Status == models.RunActive {
+		c.JSON(http.StatusConflict, gin.H{"error": fmt.Sprintf("You are already exploring %s", run.DungeonName)})
+		return
+	}
+
+	if p.Health <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You are too injured to enter a dungeon"})
+		return
+	}
+
+	run := models.NewDungeonRun(dungeon, p.ID)
+	dungeonRuns[p.ID] = run
+
+	combatLog := []string{fmt.Sprintf("You enter %s.", dungeon.Name)}
+	combatLog = resolveRoom(p, run, combatLog)
+
+	c.JSON(http.StatusOK, gin.H{
+		"player":    p,
+		"run":       run,
+		"combatLog": combatLog,
+	})
+}
+
+// generatedDungeonRequest identifies a generated dungeon. Seed defaults to a
+// random value so every player gets a different layout unless one is chosen.
+type generatedDungeonRequest struct {
+	Seed *int64 `json:"seed" form:"seed"`
+	Tier int    `json:"tier" form:"tier" binding:"required,min=1,max=20"`
+}
+
+func previewGeneratedDungeon(c *gin.Context) {
+	var request generatedDungeonRequest
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	dungeon, err := generateDungeon(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+	c.JSON(http.StatusOK, dungeon)
+}
+
+func enterGeneratedDungeon(c *gin.Context) {
+	var request generatedDungeonRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	dungeon, err := generateDungeon(request)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	startDungeonRun(c, dungeon)
+}
+
+// generateDungeon builds a dungeon from the mob catalog. Mobs are sorted by
+// name first so the same seed always produces the same layout.
+func generateDungeon(request generatedDungeonRequest) (models.Dungeon, error) {
+	seed := time.Now().UnixNano()
+	if request.Seed != nil {
+		seed = *request.Seed
+	}
+
+	mobs := make([]models.Enemy, 0, len(enemyCatalog))
+	for _, enemy := range enemyCatalog {
+		mobs = append(mobs, enemy)
+	}
+	sort.Slice(mobs, func(i, j int) bool {
+		return mobs[i].Name < mobs[j].Name
+	})
+
+	return models.GenerateDungeon(seed, request.Tier, mobs)
+}
+
+func getDungeonRun(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	run, ok := dungeonRuns[p.ID]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "You have not entered a dungeon"})
+		return
+	}
+	c.JSON(http.StatusOK, run)
+}
+
+func attackInDungeon(c *gin.Context) {
+	run, p, ok := activeDungeonRun(c)
+	if !ok {
+		return
+	}
+
+	if run.Enemy == nil || run.RoomCleared {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "There is nothing to fight in this room"})
+		return
+	}
+
+	if err := canAttack(p); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	markCombat(p, time.Now())
+	combatLog, defeated := strikeEnemy(p, run.Enemy, []string{})
+
+	if defeated {
+		run.Experience += p.CalculateExperienceGain(run.Enemy.Level)
+		combatLog = grantCombatExperience(p, *run.Enemy, combatLog)
+
+		goldFound := run.Enemy.Level * 10
+		run.Loot.Gold += goldFound
+		combatLog = append(combatLog, fmt.Sprintf("You found %d gold.", goldFound))
+
+		drops := rollLoot(run.Enemy.Name)
+		if run.CurrentRoom().Type == models.RoomBoss {
+			drops = append(drops, rollLoot("Boss Chest")...)
+		}
+		run.Loot.Items = append(run.Loot.Items, drops...)
+		combatLog = describeDrops(drops, combatLog)
+
+		run.RoomCleared = true
+		run.Enemy = nil
+		if run.IsLastRoom() {
+			combatLog = completeDungeonRun(p, run, combatLog)
+		}
+	} else {
+		combatLog = enemyRetaliates(p, *run.Enemy, combatLog)
+		combatLog = checkDungeonDeath(p, run, combatLog)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"player":    p,
+		"run":       run,
+		"combatLog": combatLog,
+	})
+}
+
+func advanceDungeon(c *gin.Context) {
+	// Exit picks which room to enter when the current room has several exits
+	request := struct {
+		Exit *int `json:"exit"`
+	}{}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+			return
+		}
+	}
+
+	run, p, ok := activeDungeonRun(c)
+	if !ok {
+		return
+	}
+
+	if !run.RoomCleared {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("You must defeat %s before moving on", run.Enemy.Name),
+		})
+		return
+	}
+
+	combatLog := []string{}
+	if run.IsLastRoom() {
+		combatLog = completeDungeonRun(p, run, combatLog)
+	} else {
+		exit := -1
+		if request.Exit != nil {
+			exit = *request.Exit
+		}
+		if err := run.Advance(exit); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+			return
+		}
+		combatLog = resolveRoom(p, run, combatLog)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"player":    p,
+		"run":       run,
+		"combatLog": combatLog,
+	})
+}
+
+func fleeDungeon(c *gin.Context) {
+	run, p, ok := activeDungeonRun(c)
+	if !ok {
+		return
+	}
+
+	run.End(models.RunFled)
+
+	c.JSON(http.StatusOK, gin.H{
+		"player": p,
+		"run":    run,
+		"combatLog": []string{
+			fmt.Sprintf("You fled %s, leaving %d gold behind.", run.DungeonName, run.Loot.Gold),
+		},
+	})
+}
+
+// activeDungeonRun returns the current player's in-progress run, writing an error response if there is none
+func activeDungeonRun(c *gin.Context) (*models.DungeonRun, *models.Player, bool) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return nil, nil, false
+	}
+
+	run, ok := dungeonRuns[p.ID]
+	if !ok || run.Status != models.RunActive {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You are not exploring a dungeon"})
+		return nil, nil, false
+	}
+	return run, p, true
+}
+
+// resolveRoom applies the effect of the room the player just walked into.
+// Encounter rooms stay uncleared until their enemy is defeated.
+func resolveRoom(p *models.Player, run *models.DungeonRun, combatLog []string) []string {
+	room := run.CurrentRoom()
+	combatLog = append(combatLog, fmt.Sprintf("Room %d of %d: %s.", run.RoomIndex+1, len(run.Rooms), room.Description))
+
+	switch room.Type {
+	case models.RoomCombat, models.RoomBoss:
+		if run.Enemy == nil {
+			run.RoomCleared = true
+			return combatLog
+		}
+		combatLog = append(combatLog, fmt.Sprintf("%s attacks!", run.Enemy.Name))
+		return combatLog
+	case models.RoomTreasure:
+		run.Loot.Gold += room.Gold
+		combatLog = append(combatLog, fmt.Sprintf("You found %d gold.", room.Gold))
+		if room.Chest != "" {
+			drops := rollLoot(room.Chest)
+			run.Loot.Items = append(run.Loot.Items, drops...)
+			combatLog = describeDrops(drops, combatLog)
+		}
+	case models.RoomTrap:
+		p.TakeDamage(room.TrapDamage)
+		p.Stamina = maximum(0, p.Stamina-room.TrapDamage)
+		combatLog = append(combatLog, fmt.Sprintf("It's a trap! You lose health and %d stamina.", room.TrapDamage))
+		combatLog = checkDungeonDeath(p, run, combatLog)
+	case models.RoomRest:
+		health, stamina := p.Recover(room.HealAmount, room.HealAmount)
+		combatLog = append(combatLog, fmt.Sprintf("You rest and recover %d health and %d stamina.", health, stamina))
+	}
+
+	run.RoomCleared = true
+	return combatLog
+}
+
+// checkDungeonDeath ends the run if the player has run out of health and
+// applies the death penalty
+func checkDungeonDeath(p *models.Player, run *models.DungeonRun, combatLog []string) []string {
+	if p.Health > 0 {
+		return combatLog
+	}
+	cause := "a trap"
+	if run.Enemy != nil {
+		cause = run.Enemy.Name
+	}
+	run.End(models.RunDead)
+	combatLog = append(combatLog, fmt.Sprintf("You have fallen in %s. All loot from this run is lost.", run.DungeonName))
+	return killPlayer(p, cause, combatLog)
+}
+
+// completeDungeonRun ends the run successfully and grants the accumulated loot
+func completeDungeonRun(p *models.Player, run *models.DungeonRun, combatLog []string) []string {
+	run.End(models.RunCompleted)
+
+	p.Gold += run.Loot.Gold
+	recordGold(p.ID, models.SourceDungeonLoot, run.Loot.Gold)
+	for _, drop := range run.Loot.Items {
+		p.AddItemToInventory(drop.Item)
+		recordItem(p.ID, models.SourceDungeonLoot, drop.Item.ID, drop.Item.Quantity)
+	}
+
+	combatLog = append(combatLog, fmt.Sprintf("You conquered %s!", run.DungeonName))
+	return append(combatLog, fmt.Sprintf("Run loot: %d gold and %d items. You earned %d experience along the way.",
+		run.Loot.Gold, len(run.Loot.Items), run.Experience))
+}
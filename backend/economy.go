@@ -0,0 +1,135 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"time"
+
+	"galycherrygame/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// marketFeePercent of every market sale is taken from the seller and removed from the game
+const marketFeePercent = 2
+
+// ledger records every gold and item source and sink in the economy
+var ledger = &models.Ledger{}
+
+// adminToken is the secret admin requests must send in the X-Admin-Token
+// header. Admin endpoints are disabled while it is empty.
+var adminToken string
+
+// requireAdmin rejects requests that don't carry the admin token
+func requireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader("X-Admin-Token")
+		if adminToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(adminToken)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Admin access required"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// recordGold logs gold received (positive) or spent (negative) by a player and
+// counts gold received towards their achievements
+func recordGold(playerID uint, source string, amount int) {
+	ledger.Record(models.LedgerEntry{
+		Time:     time.Now(),
+		PlayerID: playerID,
+		Source:   source,
+		Gold:     amount,
+	})
+	recordGoldEarned(playerID, source, amount)
+}
+
+// recordItem logs items received (positive) or given up (negative) by a player
+func recordItem(playerID uint, source string, itemID uint, quantity int) {
+	ledger.Record(models.LedgerEntry{
+		Time:         time.Now(),
+		PlayerID:     playerID,
+		Source:       source,
+		ItemID:       itemID,
+		ItemQuantity: quantity,
+	})
+}
+
+// marketFee returns the fee charged on a market sale of the given value
+func marketFee(value int) int {
+	return value * marketFeePercent / 100
+}
+
+// moneySupply totals all gold in the game, including gold held in trade and market escrow
+func moneySupply() int {
+	total := 0
+	for _, p := range players {
+		total += p.Gold
+	}
+	for _, trade := range trades {
+		if trade.Status == models.TradeOpen {
+			total += trade.Initiator.Gold + trade.Recipient.Gold
+		}
+	}
+	for _, order := range marketOrders {
+		if order.Status == models.OrderOpen && order.Side == models.OrderBuy {
+			total += order.Price * order.Remaining()
+		}
+	}
+	return total
+}
+
+// getEconomyReport returns the money supply and per-source flows over a time
+// window, split into buckets so inflation trends can be spotted
+func getEconomyReport(c *gin.Context) {
+	var request struct {
+		Window string `form:"window"`
+		Bucket string `form:"bucket"`
+	}
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	window := 24 * time.Hour
+	if request.Window != "" {
+		parsed, err := time.ParseDuration(request.Window)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid window, expected a duration such as 24h"})
+			return
+		}
+		window = parsed
+	}
+
+	bucket := time.Hour
+	if request.Bucket != "" {
+		parsed, err := time.ParseDuration(request.Bucket)
+		if err != nil || parsed <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid bucket, expected a duration such as 1h"})
+			return
+		}
+		bucket = parsed
+	}
+	if window/bucket > 1000 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Bucket is too small for the window"})
+		return
+	}
+
+	to := time.Now()
+	from := to.Add(-window)
+	flows := ledger.Flows(from, to)
+
+	netGold := 0
+	for _, flow := range flows {
+		netGold += flow.NetGold
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"moneySupply": moneySupply(),
+		"from":        from,
+		"to":          to,
+		"netGold":     netGold,
+		"flows":       flows,
+		"buckets":     ledger.Buckets(from, to, bucket),
+	})
+}
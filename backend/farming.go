@@ -0,0 +1,246 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"galycherrygame/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// plotsPerPlayer is how many farm plots every player owns
+	plotsPerPlayer = 4
+	// farmLocation is where every player's farm plots are
+	farmLocation = "Cherry Village"
+
+	wateringCanID = 38
+	compostID     = 36
+	plantCureID   = 37
+)
+
+// crops holds everything that can be grown, keyed by crop ID
+var crops = map[uint]models.Crop{
+	1: {ID: 1, Name: "Potato", SeedID: 28, ProduceID: 32, Level: 1, PlantExperience: 8, HarvestExperience: 9, Stages: 4, StageDuration: 30 * time.Second, BaseYield: 3, DiseaseChance: 0.1},
+	2: {ID: 2, Name: "Cabbage", SeedID: 29, ProduceID: 33, Level: 7, PlantExperience: 10, HarvestExperience: 12, Stages: 4, StageDuration: 45 * time.Second, BaseYield: 3, DiseaseChance: 0.15},
+	3: {ID: 3, Name: "Cherry Herb", SeedID: 30, ProduceID: 34, Level: 9, PlantExperience: 11, HarvestExperience: 13, Stages: 4, StageDuration: time.Minute, BaseYield: 2, DiseaseChance: 0.2},
+	4: {ID: 4, Name: "Moonpetal", SeedID: 31, ProduceID: 35, Level: 32, PlantExperience: 30, HarvestExperience: 36, Stages: 5, StageDuration: 2 * time.Minute, BaseYield: 2, DiseaseChance: 0.25},
+}
+
+// farmPlots holds each player's plots, keyed by player ID
+var farmPlots = map[uint][]*models.FarmPlot{}
+
+func getCrops(c *gin.Context) {
+	c.JSON(http.StatusOK, crops)
+}
+
+func getFarmPlots(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, playerPlots(p.ID, time.Now()))
+}
+
+// plantCrop sows a seed from the player's inventory in one of their plots
+func plantCrop(c *gin.Context) {
+	plot, p, ok := findPlot(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		CropID uint `json:"cropId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	crop, ok := crops[request.CropID]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Crop not found"})
+		return
+	}
+	if p.Skills.Farming < crop.Level {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Farming level %d required (current: %d)", crop.Level, p.Skills.Farming),
+		})
+		return
+	}
+	if p.ItemQuantity(crop.SeedID) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("You need a %s", itemCatalog[crop.SeedID].Name)})
+		return
+	}
+	if err := plot.Plant(crop, time.Now()); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	p.RemoveItemFromInventory(crop.SeedID, 1)
+	recordItem(p.ID, models.SourceFarming, crop.SeedID, -1)
+	p.AddSkillExperience(models.SkillFarming, crop.PlantExperience)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("You plant a %s", itemCatalog[crop.SeedID].Name),
+		"player":  p,
+		"plot":    plot,
+	})
+}
+
+func waterPlot(c *gin.Context) {
+	tendPlot(c, wateringCanID, false, "water", (*models.FarmPlot).Water)
+}
+
+func compostPlot(c *gin.Context) {
+	tendPlot(c, compostID, true, "compost", (*models.FarmPlot).Compost)
+}
+
+func curePlot(c *gin.Context) {
+	tendPlot(c, plantCureID, true, "cure", (*models.FarmPlot).Cure)
+}
+
+// tendPlot applies a tending action to a plot using an item from the
+// player's inventory, using the item up if consumed is set
+func tendPlot(c *gin.Context, itemID uint, consumed bool, verb string, tend func(*models.FarmPlot, time.Time) error) {
+	plot, p, ok := findPlot(c)
+	if !ok {
+		return
+	}
+
+	item := itemCatalog[itemID]
+	if p.ItemQuantity(itemID) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("You need %s to %s a plot", item.Name, verb)})
+		return
+	}
+	if err := tend(plot, time.Now()); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	if consumed {
+		p.RemoveItemFromInventory(itemID, 1)
+		recordItem(p.ID, models.SourceFarming, itemID, -1)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("You %s the plot with %s", verb, item.Name),
+		"player":  p,
+		"plot":    plot,
+	})
+}
+
+// harvestPlot picks a fully grown crop, emptying the plot
+func harvestPlot(c *gin.Context) {
+	plot, p, ok := findPlot(c)
+	if !ok {
+		return
+	}
+	if plot.Status != models.PlotGrown {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Plot is %s", plot.Status)})
+		return
+	}
+
+	crop := crops[plot.CropID]
+	if !p.CanAddItem(crop.ProduceID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Not enough inventory space"})
+		return
+	}
+
+	yield := plot.Yield(crop)
+	produce := catalogItem(crop.ProduceID, yield)
+	p.AddItemToInventory(produce)
+	recordItem(p.ID, models.SourceFarming, produce.ID, yield)
+	experience := crop.HarvestExperience * yield
+	levels := p.AddSkillExperience(models.SkillFarming, experience)
+	plot.Clear()
+
+	message := fmt.Sprintf("You harvest %dx %s", yield, produce.Name)
+	if levels > 0 {
+		message += fmt.Sprintf(". Your farming level is now %d!", p.Skills.Farming)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    message,
+		"player":     p,
+		"plot":       plot,
+		"produce":    produce,
+		"experience": experience,
+	})
+}
+
+// clearPlot digs up whatever is in a plot so it can be replanted
+func clearPlot(c *gin.Context) {
+	plot, p, ok := findPlot(c)
+	if !ok {
+		return
+	}
+	if plot.Status == models.PlotEmpty {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Plot is already empty"})
+		return
+	}
+
+	plot.Clear()
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "You clear the plot",
+		"player":  p,
+		"plot":    plot,
+	})
+}
+
+// playerPlots returns the player's plots brought up to date, creating them on first use
+func playerPlots(playerID uint, now time.Time) []*models.FarmPlot {
+	plots, ok := farmPlots[playerID]
+	if !ok {
+		for i := 1; i <= plotsPerPlayer; i++ {
+			plots = append(plots, &models.FarmPlot{
+				ID:               uint(i),
+				PlayerID:         playerID,
+				Location:         farmLocation,
+				Status:           models.PlotEmpty,
+				LastWateredStage: -1,
+			})
+		}
+		farmPlots[playerID] = plots
+	}
+
+	rng := rand.New(rand.NewSource(now.UnixNano()))
+	for _, plot := range plots {
+		if crop, ok := crops[plot.CropID]; ok {
+			plot.Update(crop, now, rng)
+		}
+	}
+	return plots
+}
+
+// findPlot looks up the current player's plot named by the :id route
+// parameter, writing an error response if it doesn't exist
+func findPlot(c *gin.Context) (*models.FarmPlot, *models.Player, bool) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return nil, nil, false
+	}
+	if err := atLocation(p, farmLocation); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return nil, nil, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid plot ID"})
+		return nil, nil, false
+	}
+
+	for _, plot := range playerPlots(p.ID, time.Now()) {
+		if plot.ID == uint(id) {
+			return plot, p, true
+		}
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": "Plot not found"})
+	return nil, nil, false
+}
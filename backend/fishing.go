@@ -0,0 +1,221 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"galycherrygame/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fishingSpots holds every place to fish, keyed by spot ID
+var fishingSpots = map[uint]*models.FishingSpot{
+	1: {
+		ID:       1,
+		Name:     "Cherry Village Pond",
+		Location: "Cherry Village",
+		Level:    1,
+		ToolID:   14,
+		Duration: 5 * time.Second,
+		Catches: []models.FishCatch{
+			{LootEntry: models.LootEntry{Item: catalogItem(16, 1), Weight: 80, MinQuantity: 1, MaxQuantity: 3, Rarity: models.RarityCommon}, Level: 1, Experience: 10},
+			{LootEntry: models.LootEntry{Item: catalogItem(17, 1), Weight: 20, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityUncommon}, Level: 10, Experience: 40},
+		},
+		RareCatches: []models.FishCatch{
+			{LootEntry: models.LootEntry{Item: catalogItem(21, 1), Weight: 1, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityRare}, Level: 1, Experience: 100},
+		},
+		RareChance: 200,
+	},
+	2: {
+		ID:       2,
+		Name:     "Ironforge River",
+		Location: "Ironforge",
+		Level:    15,
+		ToolID:   14,
+		BaitID:   15,
+		Duration: 8 * time.Second,
+		Catches: []models.FishCatch{
+			{LootEntry: models.LootEntry{Item: catalogItem(17, 1), Weight: 60, MinQuantity: 1, MaxQuantity: 2, Rarity: models.RarityCommon}, Level: 15, Experience: 50},
+			{LootEntry: models.LootEntry{Item: catalogItem(18, 1), Weight: 30, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityUncommon}, Level: 25, Experience: 70},
+		},
+		RareCatches: []models.FishCatch{
+			{LootEntry: models.LootEntry{Item: catalogItem(21, 1), Weight: 70, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityRare}, Level: 15, Experience: 150},
+			{LootEntry: models.LootEntry{Item: catalogItem(11, 1), Weight: 30, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityEpic}, Level: 15, Experience: 250},
+		},
+		RareChance: 150,
+	},
+	3: {
+		ID:       3,
+		Name:     "Ironforge Deep Lake",
+		Location: "Ironforge",
+		Level:    40,
+		ToolID:   14,
+		BaitID:   15,
+		Duration: 12 * time.Second,
+		Catches: []models.FishCatch{
+			{LootEntry: models.LootEntry{Item: catalogItem(19, 1), Weight: 70, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityUncommon}, Level: 40, Experience: 90},
+			{LootEntry: models.LootEntry{Item: catalogItem(20, 1), Weight: 30, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityRare}, Level: 50, Experience: 100},
+		},
+		RareCatches: []models.FishCatch{
+			{LootEntry: models.LootEntry{Item: catalogItem(11, 1), Weight: 60, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityEpic}, Level: 40, Experience: 300},
+			{LootEntry: models.LootEntry{Item: catalogItem(10, 1), Weight: 40, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityEpic}, Level: 40, Experience: 400},
+		},
+		RareChance: 100,
+	},
+}
+
+func getFishingSpots(c *gin.Context) {
+	c.JSON(http.StatusOK, fishingSpots)
+}
+
+// getFishingSpot returns a spot along with the player's chance of each catch at their fishing level
+func getFishingSpot(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+	spot, ok := findFishingSpot(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"spot":    spot,
+		"chances": spot.AvailableCatches(p.Skills.Fishing),
+	})
+}
+
+// startFishing casts a line at a spot, using up one bait if the spot needs it
+func startFishing(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+	spot, ok := findFishingSpot(c)
+	if !ok {
+		return
+	}
+
+	if err := canFish(p, spot); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	action := &models.GatheringAction{
+		PlayerID:  p.ID,
+		Skill:     models.SkillFishing,
+		SourceID:  spot.ID,
+		Source:    spot.Name,
+		StartedAt: time.Now(),
+		Duration:  spot.Duration,
+	}
+	if !startGathering(c, action) {
+		return
+	}
+	if spot.BaitID != 0 {
+		p.RemoveItemFromInventory(spot.BaitID, 1)
+		recordItem(p.ID, models.SourceFishing, spot.BaitID, -1)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("You cast your line at %s", spot.Name),
+		"player":  p,
+		"action":  action,
+	})
+}
+
+// collectCatch lands the catch once the player's fishing action has finished
+func collectCatch(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+	action, ok := finishedGathering(c, p, models.SkillFishing)
+	if !ok {
+		return
+	}
+
+	spot := fishingSpots[action.SourceID]
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	catch, drop, levels, err := landCatch(p, spot, rng)
+	if errors.Is(err, errNothingCaught) {
+		delete(gatheringActions, p.ID)
+		c.JSON(http.StatusOK, gin.H{"message": errorMessage(err), "player": p})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+	delete(gatheringActions, p.ID)
+
+	message := fmt.Sprintf("You caught %dx %s!", drop.Item.Quantity, drop.Item.Name)
+	if levels > 0 {
+		message += fmt.Sprintf(" Your fishing level is now %d!", p.Skills.Fishing)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    message,
+		"player":     p,
+		"catch":      drop,
+		"experience": catch.Experience,
+	})
+}
+
+// errNothingCaught is returned by landCatch when nothing bites
+var errNothingCaught = errors.New("nothing is biting")
+
+// landCatch rolls what the player catches at a spot and adds it to their
+// inventory, returning the catch and the fishing levels gained
+func landCatch(p *models.Player, spot *models.FishingSpot, rng *rand.Rand) (models.FishCatch, models.LootDrop, int, error) {
+	catch, drop, ok := spot.Roll(rng, p.Skills.Fishing)
+	if !ok {
+		return catch, drop, 0, errNothingCaught
+	}
+	if !p.CanAddItem(drop.Item.ID) {
+		return catch, drop, 0, errors.New("not enough inventory space")
+	}
+
+	p.AddItemToInventory(drop.Item)
+	recordItem(p.ID, models.SourceFishing, drop.Item.ID, drop.Item.Quantity)
+	levels := p.AddSkillExperience(models.SkillFishing, catch.Experience)
+	return catch, drop, levels, nil
+}
+
+// canFish checks the player is at the spot and has the level, rod and bait to fish there
+func canFish(p *models.Player, spot *models.FishingSpot) error {
+	if err := atLocation(p, spot.Location); err != nil {
+		return err
+	}
+	if p.Skills.Fishing < spot.Level {
+		return fmt.Errorf("fishing level %d required (current: %d)", spot.Level, p.Skills.Fishing)
+	}
+	if p.ItemQuantity(spot.ToolID) == 0 {
+		return fmt.Errorf("you need a %s to fish here", itemCatalog[spot.ToolID].Name)
+	}
+	if spot.BaitID != 0 && p.ItemQuantity(spot.BaitID) == 0 {
+		return fmt.Errorf("you need %s to fish here", itemCatalog[spot.BaitID].Name)
+	}
+	return nil
+}
+
+// findFishingSpot looks up the spot named by the :id route parameter, writing an error response if it doesn't exist
+func findFishingSpot(c *gin.Context) (*models.FishingSpot, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid fishing spot ID"})
+		return nil, false
+	}
+
+	spot, ok := fishingSpots[uint(id)]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Fishing spot not found"})
+		return nil, false
+	}
+	return spot, true
+}
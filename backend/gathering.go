@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"galycherrygame/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// gatheringActions holds the skilling action each player is busy with, keyed by player ID
+var gatheringActions = map[uint]*models.GatheringAction{}
+
+// startGathering begins a timed action for the player, writing an error
+// response if they are already busy with another one
+func startGathering(c *gin.Context, action *models.GatheringAction) bool {
+	if current, ok := gatheringActions[action.PlayerID]; ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("You are already %s at %s", gatheringVerb(current.Skill), current.Source),
+		})
+		return false
+	}
+	gatheringActions[action.PlayerID] = action
+	return true
+}
+
+// finishedGathering returns the player's action in one of the given skills
+// once it has finished, writing an error response if there is none or it is
+// still going
+func finishedGathering(c *gin.Context, p *models.Player, skills ...string) (*models.GatheringAction, bool) {
+	action, ok := gatheringActions[p.ID]
+	if !ok || !slices.Contains(skills, action.Skill) {
+		verb := "gathering"
+		if len(skills) == 1 {
+			verb = gatheringVerb(skills[0])
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("You are not %s", verb)})
+		return nil, false
+	}
+
+	now := time.Now()
+	if !action.IsDone(now) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":     fmt.Sprintf("Still %s at %s", gatheringVerb(action.Skill), action.Source),
+			"remaining": action.Remaining(now).Seconds(),
+		})
+		return nil, false
+	}
+	return action, true
+}
+
+// gatheringVerb describes what a player doing a gathering skill is doing
+func gatheringVerb(skill string) string {
+	switch skill {
+	case models.SkillFishing:
+		return "fishing"
+	case models.SkillMining:
+		return "mining"
+	case models.SkillWoodcutting:
+		return "woodcutting"
+	}
+	return "gathering"
+}
@@ -0,0 +1,462 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	"galycherrygame/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// idleOfflineAfter is how long without a request before a player counts as away
+	idleOfflineAfter = 5 * time.Minute
+	// idleOfflineCap is how long idle actions keep running after a player was last seen
+	idleOfflineCap = 12 * time.Hour
+	// maxIdleQueue is the most idle actions a player can have queued at once
+	maxIdleQueue = 5
+
+	craftInterval = 3 * time.Second
+	cookInterval  = 3 * time.Second
+	fightInterval = 5 * time.Second
+)
+
+// idleQueues holds each player's queued idle actions in order, keyed by player ID.
+// Only the first action in a queue runs.
+var idleQueues = map[uint][]*models.IdleAction{}
+
+var nextIdleActionID uint = 1
+
+// lastSeen holds when each player last made a request, keyed by player ID
+var lastSeen = map[uint]time.Time{}
+
+// awaySummaries holds what idle actions did for each player who is currently away
+var awaySummaries = map[uint]*models.AwaySummary{}
+
+// welcomeBackSummaries holds the summary of a player's last absence until they read it
+var welcomeBackSummaries = map[uint]*models.AwaySummary{}
+
+// lockGame serializes requests against the tick scheduler and records that the
+// requesting player is online
+func lockGame() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		gameMu.Lock()
+		defer gameMu.Unlock()
+
+		id := player.ID
+		if header := c.GetHeader("X-Player-ID"); header != "" {
+			if parsed, err := strconv.ParseUint(header, 10, 64); err == nil {
+				id = uint(parsed)
+			}
+		}
+		if _, ok := players[id]; ok {
+			markSeen(id, time.Now())
+		}
+
+		c.Next()
+	}
+}
+
+// markSeen records a request from the player, closing off their away summary if they were away
+func markSeen(playerID uint, now time.Time) {
+	if summary, ok := awaySummaries[playerID]; ok && now.Sub(lastSeen[playerID]) >= idleOfflineAfter {
+		summary.Until = now
+		welcomeBackSummaries[playerID] = summary
+		delete(awaySummaries, playerID)
+	}
+	lastSeen[playerID] = now
+}
+
+// processIdleQueues runs every idle action repetition that is due by now.
+// Repetitions due after a player's offline cap wait until they come back.
+func processIdleQueues(now time.Time) {
+	rng := rand.New(rand.NewSource(now.UnixNano()))
+	for playerID, queue := range idleQueues {
+		p, ok := players[playerID]
+		if !ok {
+			delete(idleQueues, playerID)
+			continue
+		}
+
+		cutoff := lastSeen[playerID].Add(idleOfflineCap)
+		for len(queue) > 0 {
+			action := queue[0]
+			if action.NextAt.After(now) || action.NextAt.After(cutoff) {
+				break
+			}
+
+			runIdleRepetition(p, action, rng)
+			if _, ok := idleQueues[playerID]; !ok {
+				// The player died and lost their queue
+				queue = nil
+				break
+			}
+			if action.Status == models.IdleQueued {
+				action.NextAt = action.NextAt.Add(idleInterval(p, action))
+				continue
+			}
+
+			queue = queue[1:]
+			if len(queue) > 0 {
+				queue[0].NextAt = action.NextAt.Add(idleInterval(p, queue[0]))
+			}
+		}
+
+		if len(queue) == 0 {
+			delete(idleQueues, playerID)
+		} else {
+			idleQueues[playerID] = queue
+		}
+	}
+}
+
+// runIdleRepetition performs one repetition of an action, adding what it did
+// to the player's away summary if they were away at the time
+func runIdleRepetition(p *models.Player, action *models.IdleAction, rng *rand.Rand) {
+	var summary *models.AwaySummary
+	if action.NextAt.Sub(lastSeen[p.ID]) >= idleOfflineAfter {
+		summary = awaySummaries[p.ID]
+		if summary == nil {
+			summary = models.NewAwaySummary(lastSeen[p.ID])
+			awaySummaries[p.ID] = summary
+		}
+	}
+
+	before := p.Snapshot()
+	err := performIdleAction(p, action, rng)
+	if summary != nil {
+		summary.Record(before, p.Snapshot())
+	}
+
+	switch {
+	case errors.Is(err, errIdleWaiting):
+		return
+	case err != nil:
+		action.Status = models.IdleStopped
+		action.StopReason = errorMessage(err)
+	default:
+		action.Completed++
+		if summary != nil {
+			summary.Repetitions++
+		}
+		if action.IsFinished() {
+			action.Status = models.IdleCompleted
+		}
+	}
+
+	if summary != nil && action.Status != models.IdleQueued {
+		summary.Stopped = append(summary.Stopped, describeIdleEnd(action))
+	}
+}
+
+// errIdleWaiting is returned when an action can't run yet but shouldn't stop,
+// such as when the resource it gathers from is respawning
+var errIdleWaiting = errors.New("waiting")
+
+// performIdleAction does one repetition of an idle action for the player
+func performIdleAction(p *models.Player, action *models.IdleAction, rng *rand.Rand) error {
+	switch action.Type {
+	case models.IdleFish:
+		spot := fishingSpots[action.TargetID]
+		if err := canFish(p, spot); err != nil {
+			return err
+		}
+		if spot.BaitID != 0 {
+			p.RemoveItemFromInventory(spot.BaitID, 1)
+			recordItem(p.ID, models.SourceFishing, spot.BaitID, -1)
+		}
+		if _, _, _, err := landCatch(p, spot, rng); err != nil && !errors.Is(err, errNothingCaught) {
+			return err
+		}
+		return nil
+
+	case models.IdleGather:
+		node := resourceNodes[action.TargetID]
+		if _, err := canGatherNode(p, node); err != nil {
+			return err
+		}
+		_, _, err := gatherFromNode(p, node, action.NextAt)
+		if errors.Is(err, errNodeDepleted) {
+			return errIdleWaiting
+		}
+		return err
+
+	case models.IdleCraft:
+		recipe := craftingRecipes[action.TargetID]
+		if err := canCraft(p, recipe); err != nil {
+			return err
+		}
+		craft(p, recipe)
+		return nil
+
+	case models.IdleCook:
+		recipe := cookingRecipes[action.TargetID]
+		if err := canCook(p, recipe, cookingRanges[action.StationID], 1); err != nil {
+			return err
+		}
+		cooked, _ := cook(p, recipe, 1, rng)
+		p.AddSkillExperience(models.SkillCooking, cooked*recipe.Experience)
+		return nil
+
+	case models.IdleFight:
+		return idleFight(p, action)
+	}
+	return fmt.Errorf("unknown idle action %q", action.Type)
+}
+
+// idleFight fights one enemy to the end, retreating if the player's health
+// drops below the action's threshold
+func idleFight(p *models.Player, action *models.IdleAction) error {
+	if err := canFight(p, action.Target); err != nil {
+		return err
+	}
+	if belowHealth(p, action.StopBelowHealth) {
+		return fmt.Errorf("health is below %d%%", action.StopBelowHealth)
+	}
+
+	enemy := enemyCatalog[action.Target]
+	markCombat(p, time.Now())
+	for {
+		if err := canAttack(p); err != nil {
+			return err
+		}
+		if _, defeated := strikeEnemy(p, &enemy, nil); defeated {
+			rewardKill(p, enemy, nil)
+			return nil
+		}
+		enemyRetaliates(p, enemy, nil)
+		if p.Health <= 0 {
+			killPlayer(p, enemy.Name, nil)
+			return fmt.Errorf("you were defeated by %s", enemy.Name)
+		}
+		if belowHealth(p, action.StopBelowHealth) {
+			return fmt.Errorf("health fell below %d%%, so you retreated from %s", action.StopBelowHealth, enemy.Name)
+		}
+	}
+}
+
+// belowHealth reports whether the player's health is under the given percentage of their maximum
+func belowHealth(p *models.Player, percent int) bool {
+	return p.Health*100 < percent*p.MaxHealth
+}
+
+// idleInterval returns how long one repetition of an action takes
+func idleInterval(p *models.Player, action *models.IdleAction) time.Duration {
+	switch action.Type {
+	case models.IdleFish:
+		return fishingSpots[action.TargetID].Duration
+	case models.IdleGather:
+		node := resourceNodes[action.TargetID]
+		if tool, err := canGatherNode(p, node); err == nil {
+			return node.GatherDuration(tool)
+		}
+		return node.BaseDuration
+	case models.IdleCraft:
+		return craftInterval
+	case models.IdleCook:
+		return cookInterval
+	}
+	return fightInterval
+}
+
+// describeIdleEnd returns a line for the away summary saying how an action ended
+func describeIdleEnd(action *models.IdleAction) string {
+	if action.Status == models.IdleCompleted {
+		return fmt.Sprintf("Finished %s %s %d times", action.Type, idleTargetName(action), action.Completed)
+	}
+	return fmt.Sprintf("Stopped %s %s after %d times: %s", action.Type, idleTargetName(action), action.Completed, action.StopReason)
+}
+
+// idleTargetName returns the name of what an action is performed on
+func idleTargetName(action *models.IdleAction) string {
+	switch action.Type {
+	case models.IdleFish:
+		return "at " + fishingSpots[action.TargetID].Name
+	case models.IdleGather:
+		return "at " + resourceNodes[action.TargetID].Name
+	case models.IdleCraft:
+		return craftingRecipes[action.TargetID].Name
+	case models.IdleCook:
+		return cookingRecipes[action.TargetID].Name
+	}
+	return action.Target
+}
+
+func getIdleQueue(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+	queue := idleQueues[p.ID]
+	if queue == nil {
+		queue = []*models.IdleAction{}
+	}
+	c.JSON(http.StatusOK, queue)
+}
+
+// queueIdleAction adds an action to the end of the player's idle queue after
+// checking they could do it right now
+func queueIdleAction(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Type            string `json:"type" binding:"required,oneof=fish gather craft cook fight"`
+		TargetID        uint   `json:"targetId"`
+		Target          string `json:"target"`
+		StationID       uint   `json:"stationId"`
+		Repeat          int    `json:"repeat" binding:"min=0"`
+		StopBelowHealth int    `json:"stopBelowHealth" binding:"min=0,max=100"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	if len(idleQueues[p.ID]) >= maxIdleQueue {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("You can only queue %d actions", maxIdleQueue)})
+		return
+	}
+
+	action := &models.IdleAction{
+		PlayerID:        p.ID,
+		Type:            request.Type,
+		TargetID:        request.TargetID,
+		Target:          request.Target,
+		StationID:       request.StationID,
+		Repeat:          request.Repeat,
+		StopBelowHealth: request.StopBelowHealth,
+		Status:          models.IdleQueued,
+	}
+	if status, err := validateIdleAction(p, action); err != nil {
+		c.JSON(status, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	now := time.Now()
+	action.ID = nextIdleActionID
+	nextIdleActionID++
+	action.QueuedAt = now
+	if len(idleQueues[p.ID]) == 0 {
+		action.NextAt = now.Add(idleInterval(p, action))
+	}
+	idleQueues[p.ID] = append(idleQueues[p.ID], action)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"action": action,
+		"queue":  idleQueues[p.ID],
+	})
+}
+
+// validateIdleAction checks the action's target exists and that the player
+// could perform it now, returning the status code to respond with if not
+func validateIdleAction(p *models.Player, action *models.IdleAction) (int, error) {
+	switch action.Type {
+	case models.IdleFish:
+		spot, ok := fishingSpots[action.TargetID]
+		if !ok {
+			return http.StatusNotFound, errors.New("fishing spot not found")
+		}
+		return http.StatusBadRequest, canFish(p, spot)
+	case models.IdleGather:
+		node, ok := resourceNodes[action.TargetID]
+		if !ok {
+			return http.StatusNotFound, errors.New("resource not found")
+		}
+		_, err := canGatherNode(p, node)
+		return http.StatusBadRequest, err
+	case models.IdleCraft:
+		recipe, ok := craftingRecipes[action.TargetID]
+		if !ok {
+			return http.StatusNotFound, errors.New("recipe not found")
+		}
+		return http.StatusBadRequest, canCraft(p, recipe)
+	case models.IdleCook:
+		recipe, ok := cookingRecipes[action.TargetID]
+		if !ok {
+			return http.StatusNotFound, errors.New("recipe not found")
+		}
+		station, ok := cookingRanges[action.StationID]
+		if !ok {
+			return http.StatusNotFound, errors.New("cooking range not found")
+		}
+		return http.StatusBadRequest, canCook(p, recipe, station, 1)
+	case models.IdleFight:
+		if _, ok := enemyCatalog[action.Target]; !ok {
+			return http.StatusNotFound, errors.New("enemy not found")
+		}
+		if err := canFight(p, action.Target); err != nil {
+			return http.StatusBadRequest, err
+		}
+		if err := canAttack(p); err != nil {
+			return http.StatusBadRequest, err
+		}
+		if belowHealth(p, action.StopBelowHealth) {
+			return http.StatusBadRequest, fmt.Errorf("health is below %d%%", action.StopBelowHealth)
+		}
+	}
+	return http.StatusOK, nil
+}
+
+// cancelIdleAction removes an action from the player's queue
+func cancelIdleAction(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid action ID"})
+		return
+	}
+
+	queue := idleQueues[p.ID]
+	for i, action := range queue {
+		if action.ID != uint(id) {
+			continue
+		}
+
+		action.Status = models.IdleCancelled
+		queue = append(queue[:i], queue[i+1:]...)
+		if i == 0 && len(queue) > 0 {
+			queue[0].NextAt = time.Now().Add(idleInterval(p, queue[0]))
+		}
+		if len(queue) == 0 {
+			delete(idleQueues, p.ID)
+		} else {
+			idleQueues[p.ID] = queue
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"action": action,
+			"queue":  queue,
+		})
+		return
+	}
+	c.JSON(http.StatusNotFound, gin.H{"error": "Action not found"})
+}
+
+// getAwaySummary returns what the player's idle actions did while they were
+// away, once, and then forgets it
+func getAwaySummary(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	summary := welcomeBackSummaries[p.ID]
+	delete(welcomeBackSummaries, p.ID)
+
+	c.JSON(http.StatusOK, gin.H{
+		"player":  p,
+		"summary": summary,
+	})
+}
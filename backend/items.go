@@ -0,0 +1,81 @@
+package main
+
+import (
+	"galycherrygame/backend/models"
+)
+
+// itemCatalog holds every item that can exist in the game, keyed by item ID
+var itemCatalog = map[uint]models.InventoryItem{
+	1:  {ID: 1, Name: "Iron Sword", Description: "A basic iron sword", Type: "weapon", Stats: models.ItemStats{Attack: 5, Durability: 100, MaxDurability: 100}},
+	2:  {ID: 2, Name: "Leather Armor", Description: "Basic armor", Type: "armor", Stats: models.ItemStats{Defense: 3, Durability: 100, MaxDurability: 100}},
+	3:  {ID: 3, Name: "Health Potion", Description: "Restores 20 health", Type: "consumable", Stats: models.ItemStats{Healing: 20}},
+	4:  {ID: 4, Name: "Bones", Description: "Left behind by the fallen", Type: "material"},
+	5:  {ID: 5, Name: "Goblin Ear", Description: "Proof of a goblin slain", Type: "material"},
+	6:  {ID: 6, Name: "Wolf Pelt", Description: "Thick fur used for leatherworking", Type: "material"},
+	7:  {ID: 7, Name: "Orc Tusk", Description: "A heavy tusk prized by crafters", Type: "material"},
+	8:  {ID: 8, Name: "Steel Sword", Description: "A well balanced steel blade", Type: "weapon", Stats: models.ItemStats{Attack: 10, Durability: 150, MaxDurability: 150}},
+	9:  {ID: 9, Name: "Chainmail", Description: "Interlocking rings of steel", Type: "armor", Stats: models.ItemStats{Defense: 7, Durability: 150, MaxDurability: 150}},
+	10: {ID: 10, Name: "Ruby", Description: "A flawless red gem", Type: "material"},
+	11: {ID: 11, Name: "Sapphire", Description: "A deep blue gem", Type: "material"},
+	12: {ID: 12, Name: "Dragon Scale", Description: "Said to be harder than any metal", Type: "material"},
+	13: {ID: 13, Name: "Runed Blade", Description: "A sword etched with glowing runes", Type: "weapon", Stats: models.ItemStats{Attack: 16, MagicPower: 6, Durability: 250, MaxDurability: 250}},
+	14: {ID: 14, Name: "Fishing Rod", Description: "A sturdy rod for catching fish", Type: "tool"},
+	15: {ID: 15, Name: "Fishing Bait", Description: "Wriggling bait that lures bigger fish", Type: "material"},
+	16: {ID: 16, Name: "Raw Shrimp", Description: "A handful of tiny shrimp", Type: "material"},
+	17: {ID: 17, Name: "Raw Trout", Description: "A speckled river trout", Type: "material"},
+	18: {ID: 18, Name: "Raw Salmon", Description: "A fat silver salmon", Type: "material"},
+	19: {ID: 19, Name: "Raw Lobster", Description: "Snaps at anything that comes close", Type: "material"},
+	20: {ID: 20, Name: "Raw Swordfish", Description: "A fierce fish with a long bill", Type: "material"},
+	21: {ID: 21, Name: "Pearl", Description: "A lustrous pearl found in a shell", Type: "material"},
+	22: {ID: 22, Name: "Shrimp", Description: "Tasty cooked shrimp", Type: "consumable", Stats: models.ItemStats{Healing: 10}},
+	23: {ID: 23, Name: "Trout", Description: "A nicely grilled trout", Type: "consumable", Stats: models.ItemStats{Healing: 20}},
+	24: {ID: 24, Name: "Salmon", Description: "Smoked salmon with crispy skin", Type: "consumable", Stats: models.ItemStats{Healing: 25}},
+	25: {ID: 25, Name: "Lobster", Description: "A buttery cooked lobster", Type: "consumable", Stats: models.ItemStats{Healing: 35}},
+	26: {ID: 26, Name: "Swordfish", Description: "A hearty swordfish steak", Type: "consumable", Stats: models.ItemStats{Healing: 45}},
+	27: {ID: 27, Name: "Burnt Food", Description: "Charred beyond recognition", Type: "material"},
+	28: {ID: 28, Name: "Potato Seed", Description: "Plant in a farm plot to grow potatoes", Type: "material"},
+	29: {ID: 29, Name: "Cabbage Seed", Description: "Plant in a farm plot to grow cabbages", Type: "material"},
+	30: {ID: 30, Name: "Cherry Herb Seed", Description: "Plant in a farm plot to grow cherry herbs", Type: "material"},
+	31: {ID: 31, Name: "Moonpetal Seed", Description: "Plant in a farm plot to grow moonpetals", Type: "material"},
+	32: {ID: 32, Name: "Potato", Description: "A raw potato, better baked", Type: "material"},
+	33: {ID: 33, Name: "Cabbage", Description: "A crisp green cabbage", Type: "material"},
+	34: {ID: 34, Name: "Cherry Herb", Description: "A fragrant herb used in alchemy", Type: "material"},
+	35: {ID: 35, Name: "Moonpetal", Description: "A pale flower that glows faintly, prized by alchemists", Type: "material"},
+	36: {ID: 36, Name: "Compost", Description: "Enriches a farm plot for a bigger harvest", Type: "material"},
+	37: {ID: 37, Name: "Plant Cure", Description: "Cures a diseased crop", Type: "material"},
+	38: {ID: 38, Name: "Watering Can", Description: "Keeps crops healthy and growing", Type: "tool"},
+	39: {ID: 39, Name: "Baked Potato", Description: "Hot and fluffy", Type: "consumable", Stats: models.ItemStats{Healing: 15}},
+	40: {ID: 40, Name: "Cabbage Stew", Description: "A warming bowl of stew", Type: "consumable", Stats: models.ItemStats{Healing: 22}},
+	41: {ID: 41, Name: "Bronze Pickaxe", Description: "A basic pickaxe for mining", Type: "tool"},
+	42: {ID: 42, Name: "Iron Pickaxe", Description: "Mines faster than bronze", Type: "tool"},
+	43: {ID: 43, Name: "Steel Pickaxe", Description: "Bites through rock with ease", Type: "tool"},
+	44: {ID: 44, Name: "Bronze Axe", Description: "A basic axe for chopping trees", Type: "tool"},
+	45: {ID: 45, Name: "Iron Axe", Description: "Chops faster than bronze", Type: "tool"},
+	46: {ID: 46, Name: "Steel Axe", Description: "Fells trees in a few swings", Type: "tool"},
+	47: {ID: 47, Name: "Copper Ore", Description: "Soft reddish ore", Type: "material"},
+	48: {ID: 48, Name: "Tin Ore", Description: "Dull grey ore, alloyed with copper", Type: "material"},
+	49: {ID: 49, Name: "Iron Ore", Description: "Heavy ore for forging iron", Type: "material"},
+	50: {ID: 50, Name: "Coal", Description: "Burns hot enough to make steel", Type: "material"},
+	51: {ID: 51, Name: "Logs", Description: "Plain logs from an ordinary tree", Type: "material"},
+	52: {ID: 52, Name: "Oak Logs", Description: "Sturdy oak logs", Type: "material"},
+	53: {ID: 53, Name: "Willow Logs", Description: "Supple willow logs", Type: "material"},
+	54: {ID: 54, Name: "Mini Max Cape", Description: "Worn by those halfway to mastering every skill", Type: "cape", Stats: models.ItemStats{Attack: 2, Defense: 2, MagicPower: 2}},
+	55: {ID: 55, Name: "Max Cape", Description: "Worn by masters of every skill", Type: "cape", Stats: models.ItemStats{Attack: 5, Defense: 5, MagicPower: 5}},
+	56: {ID: 56, Name: "No Life Cape", Description: "Worn by those who have done absolutely everything", Type: "cape", Stats: models.ItemStats{Attack: 8, Defense: 8, MagicPower: 8}},
+	57: {ID: 57, Name: "Shortbow", Description: "A light bow for quick shots", Type: "weapon", Style: models.StyleRanged, Stats: models.ItemStats{Attack: 4, Durability: 100, MaxDurability: 100}},
+	58: {ID: 58, Name: "Oak Longbow", Description: "A tall bow with a powerful draw", Type: "weapon", Style: models.StyleRanged, Stats: models.ItemStats{Attack: 9, Durability: 150, MaxDurability: 150}},
+	59: {ID: 59, Name: "Apprentice Staff", Description: "A simple staff that channels runes", Type: "weapon", Style: models.StyleMagic, Stats: models.ItemStats{Attack: 1, MagicPower: 5, Durability: 100, MaxDurability: 100}},
+	60: {ID: 60, Name: "Bronze Arrows", Description: "Arrows tipped with bronze", Type: "ammo", Style: models.StyleRanged},
+	61: {ID: 61, Name: "Iron Arrows", Description: "Arrows tipped with iron", Type: "ammo", Style: models.StyleRanged},
+	62: {ID: 62, Name: "Mind Rune", Description: "A rune for casting basic combat spells", Type: "ammo", Style: models.StyleMagic},
+	63: {ID: 63, Name: "Chaos Rune", Description: "A rune crackling with destructive power", Type: "ammo", Style: models.StyleMagic},
+	64: {ID: 64, Name: "Regeneration Potion", Description: "Restores 2 health every second for 30 seconds", Type: "consumable", Stats: models.ItemStats{HealingOverTime: 2, HealingDuration: 30}},
+	65: {ID: 65, Name: "Nature Rune", Description: "A rune for casting healing spells", Type: "material"},
+}
+
+// catalogItem returns the catalog item with the given ID and quantity
+func catalogItem(id uint, quantity int) models.InventoryItem {
+	item := itemCatalog[id]
+	item.Quantity = quantity
+	return item
+}
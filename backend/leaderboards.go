@@ -0,0 +1,154 @@
+package main
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"galycherrygame/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// leaderboardInterval is how often every player's scores are submitted to the leaderboards
+	leaderboardInterval = 5 * time.Second
+	// defaultLeaderboardPageSize and maxLeaderboardPageSize bound how many entries a page holds
+	defaultLeaderboardPageSize = 20
+	maxLeaderboardPageSize     = 100
+)
+
+// Leaderboard categories that aren't a skill or a cape
+const (
+	leaderboardLevel        = "level"
+	leaderboardSlayer       = "slayer"
+	leaderboardAchievements = "achievements"
+)
+
+// leaderboards holds every leaderboard, keyed by category. Each skill ranks
+// by experience and each cape ranks by who claimed it first. They are kept
+// sorted in memory and fed by the leaderboards tick rather than queried from
+// indexed tables, because players aren't stored in the database.
+var leaderboards = newLeaderboards()
+
+func newLeaderboards() map[string]*models.Leaderboard {
+	boards := map[string]*models.Leaderboard{}
+	categories := append([]string{leaderboardLevel, leaderboardSlayer, leaderboardAchievements}, models.SkillNames...)
+	for _, cape := range capes {
+		categories = append(categories, capeCategory(cape))
+	}
+	for _, category := range categories {
+		boards[category] = models.NewLeaderboard(category)
+	}
+	return boards
+}
+
+// capeCategory returns the leaderboard category for first claims of a cape,
+// such as max-cape
+func capeCategory(cape models.Cape) string {
+	return strings.ReplaceAll(strings.ToLower(cape.Name), " ", "-")
+}
+
+// updateLeaderboards submits the player's current scores. Scores that
+// haven't changed keep the time they were first reached.
+func updateLeaderboards(p *models.Player, now time.Time) {
+	submit := func(category string, score, tiebreak int64) {
+		leaderboards[category].Submit(models.LeaderboardEntry{
+			PlayerID:   p.ID,
+			Name:       p.Name,
+			Score:      score,
+			Tiebreak:   tiebreak,
+			AchievedAt: now,
+		})
+	}
+
+	submit(leaderboardLevel, int64(p.Level), int64(p.Experience))
+	for _, skill := range p.SkillProgress() {
+		submit(skill.Skill, int64(skill.Experience), 0)
+	}
+	submit(leaderboardSlayer, int64(statsFor(p.ID).SlayerPoints), 0)
+	submit(leaderboardAchievements, int64(len(p.Achievements)), 0)
+}
+
+// recordCapeClaim puts the player on the cape's first-to-claim leaderboard
+func recordCapeClaim(p *models.Player, cape models.Cape, now time.Time) {
+	board := leaderboards[capeCategory(cape)]
+	if _, ok := board.Rank(p.ID); ok {
+		return
+	}
+	board.Submit(models.LeaderboardEntry{PlayerID: p.ID, Name: p.Name, AchievedAt: now})
+}
+
+// getLeaderboardCategories lists every leaderboard category
+func getLeaderboardCategories(c *gin.Context) {
+	categories := make([]string, 0, len(leaderboards))
+	for category := range leaderboards {
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	c.JSON(http.StatusOK, categories)
+}
+
+// getLeaderboard returns one page of a leaderboard
+func getLeaderboard(c *gin.Context) {
+	board, ok := findLeaderboard(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Page     int `form:"page" binding:"omitempty,min=1,max=1000000"`
+		PageSize int `form:"pageSize" binding:"omitempty,min=1"`
+	}
+	if err := c.ShouldBindQuery(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+	page := max(request.Page, 1)
+	pageSize := defaultLeaderboardPageSize
+	if request.PageSize > 0 {
+		pageSize = min(request.PageSize, maxLeaderboardPageSize)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"category": board.Category,
+		"page":     page,
+		"pageSize": pageSize,
+		"total":    board.Len(),
+		"entries":  board.Page((page-1)*pageSize, pageSize),
+	})
+}
+
+// getMyRank returns the current player's position on a leaderboard
+func getMyRank(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+	board, ok := findLeaderboard(c)
+	if !ok {
+		return
+	}
+
+	entry, ok := board.Rank(p.ID)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "You are not on this leaderboard yet"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"category": board.Category,
+		"total":    board.Len(),
+		"entry":    entry,
+	})
+}
+
+// findLeaderboard looks up the leaderboard named by the :category route parameter, writing an error response if it doesn't exist
+func findLeaderboard(c *gin.Context) (*models.Leaderboard, bool) {
+	board, ok := leaderboards[c.Param("category")]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Leaderboard not found"})
+		return nil, false
+	}
+	return board, true
+}
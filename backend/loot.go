@@ -0,0 +1,191 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"galycherrygame/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// rareDropTable is shared by every mob that has access to the rare drop table
+var rareDropTable = []models.LootEntry{
+	{Item: catalogItem(11, 1), Weight: 60, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityRare},
+	{Item: catalogItem(10, 1), Weight: 30, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityEpic},
+	{Item: catalogItem(12, 1), Weight: 10, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityLegendary},
+}
+
+// lootTables holds the drops for each mob and chest, keyed by mob or chest name
+var lootTables = map[string]models.LootTable{
+	"Goblin": {
+		Guaranteed: []models.LootEntry{
+			{Item: catalogItem(4, 1), MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityCommon},
+		},
+		Entries: []models.LootEntry{
+			{Item: catalogItem(5, 1), Weight: 50, MinQuantity: 1, MaxQuantity: 2, Rarity: models.RarityCommon},
+			{Item: catalogItem(3, 1), Weight: 10, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityUncommon},
+		},
+		NothingWeight:   40,
+		Rolls:           1,
+		RareTable:       rareDropTable,
+		RareTableChance: 128,
+	},
+	"Wolf": {
+		Guaranteed: []models.LootEntry{
+			{Item: catalogItem(4, 1), MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityCommon},
+		},
+		Entries: []models.LootEntry{
+			{Item: catalogItem(6, 1), Weight: 70, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityCommon},
+		},
+		NothingWeight:   30,
+		Rolls:           1,
+		RareTable:       rareDropTable,
+		RareTableChance: 100,
+	},
+	"Orc": {
+		Guaranteed: []models.LootEntry{
+			{Item: catalogItem(4, 1), MinQuantity: 1, MaxQuantity: 2, Rarity: models.RarityCommon},
+		},
+		Entries: []models.LootEntry{
+			{Item: catalogItem(7, 1), Weight: 50, MinQuantity: 1, MaxQuantity: 2, Rarity: models.RarityCommon},
+			{Item: catalogItem(3, 1), Weight: 20, MinQuantity: 1, MaxQuantity: 2, Rarity: models.RarityUncommon},
+			{Item: catalogItem(8, 1), Weight: 5, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityRare},
+		},
+		NothingWeight:   25,
+		Rolls:           2,
+		RareTable:       rareDropTable,
+		RareTableChance: 64,
+	},
+	"Goblin Chief": {
+		Guaranteed: []models.LootEntry{
+			{Item: catalogItem(5, 1), MinQuantity: 3, MaxQuantity: 5, Rarity: models.RarityCommon},
+		},
+		Entries: []models.LootEntry{
+			{Item: catalogItem(9, 1), Weight: 20, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityRare},
+			{Item: catalogItem(3, 1), Weight: 80, MinQuantity: 1, MaxQuantity: 3, Rarity: models.RarityUncommon},
+		},
+		Rolls:           1,
+		RareTable:       rareDropTable,
+		RareTableChance: 20,
+	},
+	"Orc Warlord": {
+		Guaranteed: []models.LootEntry{
+			{Item: catalogItem(7, 1), MinQuantity: 2, MaxQuantity: 4, Rarity: models.RarityCommon},
+		},
+		Entries: []models.LootEntry{
+			{Item: catalogItem(8, 1), Weight: 40, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityRare},
+			{Item: catalogItem(13, 1), Weight: 5, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityEpic},
+		},
+		NothingWeight:   55,
+		Rolls:           1,
+		RareTable:       rareDropTable,
+		RareTableChance: 10,
+	},
+	// Bosses of generated dungeons
+	"Goblin Overlord": {
+		Guaranteed: []models.LootEntry{
+			{Item: catalogItem(5, 1), MinQuantity: 4, MaxQuantity: 6, Rarity: models.RarityCommon},
+		},
+		Entries: []models.LootEntry{
+			{Item: catalogItem(3, 1), Weight: 60, MinQuantity: 1, MaxQuantity: 3, Rarity: models.RarityUncommon},
+			{Item: catalogItem(9, 1), Weight: 20, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityRare},
+		},
+		NothingWeight:   20,
+		Rolls:           1,
+		RareTable:       rareDropTable,
+		RareTableChance: 16,
+	},
+	"Wolf Overlord": {
+		Guaranteed: []models.LootEntry{
+			{Item: catalogItem(6, 1), MinQuantity: 2, MaxQuantity: 4, Rarity: models.RarityCommon},
+		},
+		Entries: []models.LootEntry{
+			{Item: catalogItem(3, 1), Weight: 60, MinQuantity: 1, MaxQuantity: 3, Rarity: models.RarityUncommon},
+			{Item: catalogItem(58, 1), Weight: 15, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityRare},
+		},
+		NothingWeight:   25,
+		Rolls:           1,
+		RareTable:       rareDropTable,
+		RareTableChance: 16,
+	},
+	"Orc Overlord": {
+		Guaranteed: []models.LootEntry{
+			{Item: catalogItem(7, 1), MinQuantity: 3, MaxQuantity: 5, Rarity: models.RarityCommon},
+		},
+		Entries: []models.LootEntry{
+			{Item: catalogItem(8, 1), Weight: 40, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityRare},
+			{Item: catalogItem(13, 1), Weight: 10, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityEpic},
+		},
+		NothingWeight:   50,
+		Rolls:           1,
+		RareTable:       rareDropTable,
+		RareTableChance: 8,
+	},
+	// Opened after clearing a treasure room
+	"Dungeon Chest": {
+		Entries: []models.LootEntry{
+			{Item: catalogItem(3, 1), Weight: 50, MinQuantity: 1, MaxQuantity: 2, Rarity: models.RarityCommon},
+			{Item: catalogItem(11, 1), Weight: 10, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityRare},
+			{Item: catalogItem(9, 1), Weight: 5, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityRare},
+		},
+		NothingWeight:   35,
+		Rolls:           2,
+		RareTable:       rareDropTable,
+		RareTableChance: 50,
+	},
+	// Rolled in addition to the boss's own table when a dungeon boss falls
+	"Boss Chest": {
+		Guaranteed: []models.LootEntry{
+			{Item: catalogItem(3, 1), MinQuantity: 2, MaxQuantity: 3, Rarity: models.RarityCommon},
+		},
+		Entries: []models.LootEntry{
+			{Item: catalogItem(8, 1), Weight: 30, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityRare},
+			{Item: catalogItem(9, 1), Weight: 30, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityRare},
+			{Item: catalogItem(13, 1), Weight: 5, MinQuantity: 1, MaxQuantity: 1, Rarity: models.RarityEpic},
+		},
+		NothingWeight:   35,
+		Rolls:           1,
+		RareTable:       rareDropTable,
+		RareTableChance: 8,
+	},
+}
+
+// rollLoot rolls the named loot table, returning no drops if the table does not exist
+func rollLoot(name string) []models.LootDrop {
+	table, ok := lootTables[name]
+	if !ok {
+		return nil
+	}
+	rng := rand.New(rand.NewSource(time.Now().UnixNano()))
+	return table.Roll(rng)
+}
+
+// describeDrops adds a combat log line for each drop
+func describeDrops(drops []models.LootDrop, combatLog []string) []string {
+	for _, drop := range drops {
+		combatLog = append(combatLog, fmt.Sprintf("Loot: %dx %s (%s)", drop.Item.Quantity, drop.Item.Name, drop.Rarity))
+	}
+	return combatLog
+}
+
+// getEnemyDrops returns a wiki-style view of everything an enemy can drop and how often
+func getEnemyDrops(c *gin.Context) {
+	name := c.Param("id")
+	table, hasTable := lootTables[name]
+	if _, isEnemy := enemyCatalog[name]; !isEnemy && !hasTable {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Enemy not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"enemy":           name,
+		"guaranteed":      table.Guaranteed,
+		"drops":           table.DropChances(),
+		"rolls":           table.Rolls,
+		"rareDrops":       table.RareDropChances(),
+		"rareTableChance": table.RareTableChance,
+	})
+}
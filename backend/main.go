@@ -9,6 +9,7 @@ package main
 // - Initializes the SQLite database using `db.InitDB()`.
 // - Configures and starts the Gin web server.
 // - Serves static assets and handles SPA routing.
+// - Runs time-based game systems on a fixed tick and shuts down gracefully on SIGINT or SIGTERM.
 // Key Features:
 // - Supports running database migrations with the `-migrate` flag.
 // - Uses Gin framework for HTTP routing and middleware.
@@ -16,13 +17,23 @@ package main
 // Environment Variables:
 // - `DB_PATH`: Path to the SQLite database file (default: `game.db`).
 // - `PORT`: Port number for the web server (default: `8080`).
+// - `PROGRESSION_CONFIG`: Optional JSON file overriding the level curve and per level rewards.
+// - `DEATH_CONFIG`: Optional JSON file overriding the death penalty and respawn health.
+// - `TICK_RATE`: Time between game ticks as a Go duration such as `500ms` (default: `1s`).
 
 import (
+	"context"     // For the graceful shutdown deadline
+	"errors"      // For recognising a closed server
 	"flag"        // For parsing command-line flags
 	"log"         // For logging errors and status messages
+	"net/http"    // For running the HTTP server
 	"os"          // For environment variable access
+	"os/signal"   // For catching shutdown signals
 	"path/filepath" // For working with file paths
+	"syscall"     // For the SIGTERM signal
+	"time"        // For the tick rate and shutdown timeout
 
+	"galycherrygame/backend/pkg/tick" // Game tick scheduler
 	"galycherrygame/db" // Custom package for database initialization and migrations
 
 	"github.com/gin-gonic/gin" // Gin web framework for handling HTTP requests
@@ -65,6 +76,56 @@ func main() {
 		return
 	}
 
+	// Load the level curve and per level rewards from PROGRESSION_CONFIG if it is set.
+	// Otherwise the defaults in models.DefaultProgression are used.
+	if path := os.Getenv("PROGRESSION_CONFIG"); path != "" {
+		if err := loadProgressionConfig(path); err != nil {
+			log.Fatal("Failed to load progression config:", err)
+		}
+	}
+
+	// Load the death penalty from DEATH_CONFIG if it is set.
+	// Otherwise the defaults in models.DefaultDeathConfig are used.
+	if path := os.Getenv("DEATH_CONFIG"); path != "" {
+		if err := loadDeathConfig(path); err != nil {
+			log.Fatal("Failed to load death config:", err)
+		}
+	}
+
+	// Put players back where they were standing when the server stopped.
+	if err := loadPlayerLocations(); err != nil {
+		log.Println("Failed to load player locations, starting everyone at their default location:", err)
+	}
+
+	// Load every player's death history.
+	if err := loadDeathRecords(); err != nil {
+		log.Println("Failed to load death records:", err)
+	}
+
+	// Admin endpoints such as the economy report need the ADMIN_TOKEN secret.
+	// They are disabled if it isn't set.
+	adminToken = os.Getenv("ADMIN_TOKEN")
+
+	// Read the tick rate from TICK_RATE, defaulting to one tick per second.
+	tickRate := defaultTickRate
+	if value := os.Getenv("TICK_RATE"); value != "" {
+		tickRate, err = time.ParseDuration(value)
+		if err != nil {
+			log.Fatal("Invalid TICK_RATE:", err)
+		}
+	}
+
+	// Start the tick scheduler, which runs idle actions, regeneration, growth, restocks and respawns.
+	// It first catches up on the ticks missed since the last processed tick was saved.
+	scheduler, err := newGameScheduler(tickRate, tick.RealClock{})
+	if err != nil {
+		log.Fatal("Failed to set up game ticks:", err)
+	}
+	if err := scheduler.Resume(); err != nil {
+		log.Println("Failed to load the last game tick, starting from now:", err)
+	}
+	scheduler.Start()
+
 	// Initialize the Gin router for handling HTTP requests.
 	router := gin.Default()
 
@@ -90,6 +151,26 @@ func main() {
 
 	// Log the server start message and begin listening on the specified port.
 	// If the server fails to start, log the error and terminate the program.
-	log.Printf("Server running on port %s", port)
-	log.Fatal(router.Run(":" + port))
+	server := &http.Server{Addr: ":" + port, Handler: router}
+	go func() {
+		log.Printf("Server running on port %s", port)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	// Wait for SIGINT or SIGTERM, then let in-flight requests finish before stopping the tick
+	// scheduler so the last processed tick is saved.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	<-ctx.Done()
+
+	log.Println("Shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Println("Failed to shut down the server cleanly:", err)
+	}
+	scheduler.Stop()
+	log.Println("Server stopped")
 }
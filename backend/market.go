@@ -0,0 +1,207 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"galycherrygame/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// orderBooks holds the market for each item, keyed by item ID
+var orderBooks = map[uint]*models.OrderBook{}
+
+// marketOrders holds every order ever placed, keyed by order ID
+var marketOrders = map[uint]*models.Order{}
+
+var nextOrderID uint = 1
+
+// placeOrder escrows the gold or items for a new order and matches it against the book
+func placeOrder(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	// Bounds keep price times quantity well within an int
+	var request struct {
+		ItemID   uint   `json:"itemId" binding:"required"`
+		Side     string `json:"side" binding:"required,oneof=buy sell"`
+		Price    int    `json:"price" binding:"required,min=1,max=1000000000"`
+		Quantity int    `json:"quantity" binding:"required,min=1,max=1000000"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	item, ok := itemCatalog[request.ItemID]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+
+	// Escrow what the order could cost up front so it can always be settled
+	switch request.Side {
+	case models.OrderBuy:
+		cost := request.Price * request.Quantity
+		if p.Gold < cost {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("Insufficient gold: %d required (current: %d)", cost, p.Gold),
+			})
+			return
+		}
+		if !p.CanAddItem(item.ID) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Not enough inventory space"})
+			return
+		}
+		p.Gold -= cost
+	case models.OrderSell:
+		if !p.RemoveItemFromInventory(item.ID, request.Quantity) {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": fmt.Sprintf("You do not have %d %s to sell", request.Quantity, item.Name),
+			})
+			return
+		}
+	}
+
+	now := time.Now()
+	order := &models.Order{
+		ID:        nextOrderID,
+		PlayerID:  p.ID,
+		ItemID:    item.ID,
+		Side:      request.Side,
+		Price:     request.Price,
+		Quantity:  request.Quantity,
+		CreatedAt: now,
+	}
+	nextOrderID++
+	marketOrders[order.ID] = order
+
+	trades := orderBook(item.ID).Place(order, now)
+	for _, trade := range trades {
+		settleMarketTrade(trade)
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"player": p,
+		"order":  order,
+		"trades": trades,
+	})
+}
+
+func getMyOrders(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	orders := []*models.Order{}
+	for _, order := range marketOrders {
+		if order.PlayerID == p.ID {
+			orders = append(orders, order)
+		}
+	}
+	c.JSON(http.StatusOK, orders)
+}
+
+// cancelOrder pulls an open order from the book and refunds whatever is still escrowed
+func cancelOrder(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid order ID"})
+		return
+	}
+
+	order, ok := marketOrders[uint(id)]
+	if !ok || order.PlayerID != p.ID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Order not found"})
+		return
+	}
+	if order.Status != models.OrderOpen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Order is %s", order.Status)})
+		return
+	}
+
+	orderBook(order.ItemID).Cancel(order)
+	if order.Side == models.OrderBuy {
+		p.Gold += order.Price * order.Remaining()
+	} else {
+		p.AddItemToInventory(catalogItem(order.ItemID, order.Remaining()))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"player": p,
+		"order":  order,
+	})
+}
+
+// getMarket returns an item's order book and recent trade history for price discovery
+func getMarket(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("itemId"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid item ID"})
+		return
+	}
+
+	item, ok := itemCatalog[uint(id)]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Item not found"})
+		return
+	}
+
+	book := orderBook(item.ID)
+	buys, sells := book.Depth()
+
+	c.JSON(http.StatusOK, gin.H{
+		"item":    item,
+		"buys":    buys,
+		"sells":   sells,
+		"history": book.History,
+	})
+}
+
+// orderBook returns the book for an item, creating it on first use
+func orderBook(itemID uint) *models.OrderBook {
+	book, ok := orderBooks[itemID]
+	if !ok {
+		book = &models.OrderBook{ItemID: itemID}
+		orderBooks[itemID] = book
+	}
+	return book
+}
+
+// settleMarketTrade releases escrow for a fill: the buyer gets the items and
+// any overpaid gold back, and the seller gets paid less the market fee
+func settleMarketTrade(trade models.MarketTrade) {
+	buyOrder := marketOrders[trade.BuyOrderID]
+	value := trade.Price * trade.Quantity
+	fee := marketFee(value)
+
+	if buyer, ok := players[trade.BuyerID]; ok {
+		buyer.AddItemToInventory(catalogItem(trade.ItemID, trade.Quantity))
+		buyer.Gold += (buyOrder.Price - trade.Price) * trade.Quantity
+	}
+	if seller, ok := players[trade.SellerID]; ok {
+		seller.Gold += value - fee
+	}
+
+	recordGold(trade.BuyerID, models.SourceMarketTrade, -value)
+	recordItem(trade.BuyerID, models.SourceMarketTrade, trade.ItemID, trade.Quantity)
+	recordGold(trade.SellerID, models.SourceMarketTrade, value)
+	recordItem(trade.SellerID, models.SourceMarketTrade, trade.ItemID, -trade.Quantity)
+	recordGold(trade.SellerID, models.SourceMarketFee, -fee)
+
+	// Matching your own orders only moves gold between your own pockets
+	if trade.SellerID != trade.BuyerID {
+		addGoldEarned(trade.SellerID, value-fee)
+	}
+}
@@ -0,0 +1,159 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Achievement criterion types
+const (
+	CriterionKills           = "kills"
+	CriterionSkillLevel      = "skill_level"
+	CriterionGoldEarned      = "gold_earned"
+	CriterionQuestsCompleted = "quests_completed"
+	CriterionItemsCrafted    = "items_crafted"
+)
+
+// AchievementCriterion is one checkable condition of an achievement. Target
+// names the enemy for kills or the skill for skill levels, and ItemID the
+// item for items crafted. An empty target or zero item ID counts everything.
+type AchievementCriterion struct {
+	Type   string `json:"type"`
+	Target string `json:"target,omitempty"`
+	ItemID uint   `json:"itemId,omitempty"`
+	Amount int    `json:"amount"`
+}
+
+// AchievementReward is what a player receives for completing an achievement
+type AchievementReward struct {
+	Gold  int             `json:"gold,omitempty"`
+	Items []InventoryItem `json:"items,omitempty"`
+	Title string          `json:"title,omitempty"`
+}
+
+// String describes the reward for the free text reward column of completed achievements
+func (r AchievementReward) String() string {
+	parts := []string{}
+	if r.Gold > 0 {
+		parts = append(parts, fmt.Sprintf("%d gold", r.Gold))
+	}
+	for _, item := range r.Items {
+		parts = append(parts, fmt.Sprintf("%dx %s", item.Quantity, item.Name))
+	}
+	if r.Title != "" {
+		parts = append(parts, fmt.Sprintf("the title %q", r.Title))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// AchievementDefinition describes an achievement every player can earn by
+// meeting all of its criteria
+type AchievementDefinition struct {
+	ID          uint                   `json:"id"`
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Criteria    []AchievementCriterion `json:"criteria"`
+	Reward      AchievementReward      `json:"reward"`
+}
+
+// PlayerStats counts the things a player has done that can't be read off the
+// player itself
+type PlayerStats struct {
+	Kills        map[string]int `json:"kills"`
+	GoldEarned   int            `json:"goldEarned"`
+	ItemsCrafted map[uint]int   `json:"itemsCrafted"`
+	// SlayerPoints are earned for every kill. There are no slayer tasks yet,
+	// so each kill is worth the enemy's level.
+	SlayerPoints int `json:"slayerPoints"`
+	Deaths       int `json:"deaths"`
+}
+
+// NewPlayerStats returns stats for a player who hasn't done anything yet
+func NewPlayerStats() *PlayerStats {
+	return &PlayerStats{
+		Kills:        map[string]int{},
+		ItemsCrafted: map[uint]int{},
+	}
+}
+
+// TotalKills returns how many enemies the player has defeated
+func (s *PlayerStats) TotalKills() int {
+	total := 0
+	for _, kills := range s.Kills {
+		total += kills
+	}
+	return total
+}
+
+// TotalCrafted returns how many items the player has crafted
+func (s *PlayerStats) TotalCrafted() int {
+	total := 0
+	for _, crafted := range s.ItemsCrafted {
+		total += crafted
+	}
+	return total
+}
+
+// CriterionProgress is how far a player is towards one criterion
+type CriterionProgress struct {
+	AchievementCriterion
+	Current int  `json:"current"`
+	Met     bool `json:"met"`
+}
+
+// Progress returns how far the player is towards each of the achievement's criteria
+func (d AchievementDefinition) Progress(p *Player, stats *PlayerStats) []CriterionProgress {
+	progress := make([]CriterionProgress, 0, len(d.Criteria))
+	for _, criterion := range d.Criteria {
+		current := criterion.current(p, stats)
+		progress = append(progress, CriterionProgress{
+			AchievementCriterion: criterion,
+			Current:              min(current, criterion.Amount),
+			Met:                  current >= criterion.Amount,
+		})
+	}
+	return progress
+}
+
+// IsMet reports whether the player meets every criterion of the achievement
+func (d AchievementDefinition) IsMet(p *Player, stats *PlayerStats) bool {
+	for _, criterion := range d.Criteria {
+		if criterion.current(p, stats) < criterion.Amount {
+			return false
+		}
+	}
+	return true
+}
+
+// current returns the player's count towards the criterion
+func (c AchievementCriterion) current(p *Player, stats *PlayerStats) int {
+	switch c.Type {
+	case CriterionKills:
+		if c.Target == "" {
+			return stats.TotalKills()
+		}
+		return stats.Kills[c.Target]
+	case CriterionSkillLevel:
+		return p.SkillLevel(c.Target)
+	case CriterionGoldEarned:
+		return stats.GoldEarned
+	case CriterionQuestsCompleted:
+		return len(p.CompletedQuests)
+	case CriterionItemsCrafted:
+		if c.ItemID == 0 {
+			return stats.TotalCrafted()
+		}
+		return stats.ItemsCrafted[c.ItemID]
+	}
+	return 0
+}
+
+// HasAchievement reports whether the player has completed the achievement
+func (p *Player) HasAchievement(achievementID uint) bool {
+	for _, achievement := range p.Achievements {
+		if achievement.AchievementID == achievementID {
+			return true
+		}
+	}
+	return false
+}
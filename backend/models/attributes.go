@@ -0,0 +1,86 @@
+package models
+
+import (
+	"fmt"
+)
+
+// AttributeAllocation is a number of skill points to put into each attribute
+type AttributeAllocation struct {
+	Strength  int `json:"strength"`
+	Dexterity int `json:"dexterity"`
+	Magic     int `json:"magic"`
+}
+
+// Total returns the number of skill points the allocation spends
+func (a AttributeAllocation) Total() int {
+	return a.Strength + a.Dexterity + a.Magic
+}
+
+// AllocateSkillPoints spends unspent skill points on attributes. Nothing is
+// changed if the allocation is invalid or costs more points than the player has.
+func (p *Player) AllocateSkillPoints(allocation AttributeAllocation, config ProgressionConfig) error {
+	if allocation.Strength < 0 || allocation.Dexterity < 0 || allocation.Magic < 0 {
+		return fmt.Errorf("cannot allocate a negative number of points")
+	}
+	// Checking each attribute first keeps the total from overflowing
+	for _, points := range []int{allocation.Strength, allocation.Dexterity, allocation.Magic} {
+		if points > p.SkillPoints {
+			return fmt.Errorf("not enough skill points: %d required (available: %d)", points, p.SkillPoints)
+		}
+	}
+	total := allocation.Total()
+	if total == 0 {
+		return fmt.Errorf("no points allocated")
+	}
+	if total > p.SkillPoints {
+		return fmt.Errorf("not enough skill points: %d required (available: %d)", total, p.SkillPoints)
+	}
+
+	p.SkillPoints -= total
+	p.Strength += allocation.Strength
+	p.Dexterity += allocation.Dexterity
+	p.Magic += allocation.Magic
+	p.RecalculateMaxStamina(config)
+	return nil
+}
+
+// ResetAttributes returns every point spent on attributes to the unspent
+// pool and returns how many points were refunded
+func (p *Player) ResetAttributes(config ProgressionConfig) int {
+	refunded := p.Strength + p.Dexterity + p.Magic
+	p.SkillPoints += refunded
+	p.Strength = 0
+	p.Dexterity = 0
+	p.Magic = 0
+	p.RecalculateMaxStamina(config)
+	return refunded
+}
+
+// RecalculateMaxStamina derives max stamina from level and dexterity, keeping
+// current stamina within the new maximum
+func (p *Player) RecalculateMaxStamina(config ProgressionConfig) {
+	p.MaxStamina = config.BaseStamina + (p.Level-1)*config.StaminaPerLevel + p.Dexterity*config.StaminaPerDexterity
+	if p.Stamina > p.MaxStamina {
+		p.Stamina = p.MaxStamina
+	}
+}
+
+// DerivedStats are the combat numbers that follow from a player's attributes
+type DerivedStats struct {
+	PhysicalDamage int `json:"physicalDamage"`
+	RangedDamage   int `json:"rangedDamage"`
+	MagicDamage    int `json:"magicDamage"`
+	Defense        int `json:"defense"`
+	MaxStamina     int `json:"maxStamina"`
+}
+
+// DerivedStats returns the player's current damage, defense and stamina
+func (p *Player) DerivedStats() DerivedStats {
+	return DerivedStats{
+		PhysicalDamage: p.CalculateAttackDamage("physical"),
+		RangedDamage:   p.CalculateAttackDamage("ranged"),
+		MagicDamage:    p.CalculateAttackDamage("magic"),
+		Defense:        p.CalculateDefense(),
+		MaxStamina:     p.MaxStamina,
+	}
+}
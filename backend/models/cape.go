@@ -0,0 +1,67 @@
+package models
+
+// Cape requirement types
+const (
+	// RequireAllSkills needs every skill at Level or higher
+	RequireAllSkills = "all_skills"
+	// RequireAllQuests needs every quest completed
+	RequireAllQuests = "all_quests"
+	// RequireAllAchievements needs every achievement completed
+	RequireAllAchievements = "all_achievements"
+	// RequireAllSlayerTiers needs the top slayer tier against every enemy.
+	// There are no slayer tasks yet, so a tier is reached by defeating an
+	// enemy Level times.
+	RequireAllSlayerTiers = "all_slayer_tiers"
+)
+
+// CapeRequirement is one requirement a player must meet to claim a cape
+type CapeRequirement struct {
+	Type  string `json:"type"`
+	Level int    `json:"level,omitempty"`
+}
+
+// Cape is a milestone reward a player can buy once they meet all of its
+// requirements. Its perks come from the stats of the cape item.
+type Cape struct {
+	ID           uint              `json:"id"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	ItemID       uint              `json:"itemId"`
+	Cost         int               `json:"cost"`
+	Requirements []CapeRequirement `json:"requirements"`
+}
+
+// RequirementStatus reports whether a player meets a cape requirement and,
+// if not, what is still missing
+type RequirementStatus struct {
+	CapeRequirement
+	Met     bool     `json:"met"`
+	Missing []string `json:"missing,omitempty"`
+}
+
+// CapeEligibility is whether a player can claim a cape and why
+type CapeEligibility struct {
+	Cape         Cape                `json:"cape"`
+	Eligible     bool                `json:"eligible"`
+	Requirements []RequirementStatus `json:"requirements"`
+}
+
+// EquipCape moves a cape from the inventory into the cape slot, returning
+// any cape that was already equipped to the inventory
+func (p *Player) EquipCape(item InventoryItem) {
+	p.RemoveItemFromInventory(item.ID, 1)
+	p.UnequipCape()
+	item.Quantity = 1
+	p.EquippedCape = &item
+	p.Cape = item.Name
+}
+
+// UnequipCape returns the equipped cape to the inventory
+func (p *Player) UnequipCape() {
+	if p.EquippedCape == nil {
+		return
+	}
+	p.AddItemToInventory(*p.EquippedCape)
+	p.EquippedCape = nil
+	p.Cape = ""
+}
@@ -0,0 +1,178 @@
+package models
+
+import "fmt"
+
+// Combat styles a player can fight with
+const (
+	StyleMelee  = "melee"
+	StyleRanged = "ranged"
+	StyleMagic  = "magic"
+)
+
+// DamageType returns the damage type CalculateAttackDamage uses for a combat style
+func DamageType(style string) string {
+	switch style {
+	case StyleRanged:
+		return "ranged"
+	case StyleMagic:
+		return "magic"
+	}
+	return "physical"
+}
+
+// Ammo is an item used up by each attack in a combat style, such as arrows
+// for ranged attacks and runes for magic. Damage is added to the attack.
+type Ammo struct {
+	ItemID uint   `json:"itemId"`
+	Style  string `json:"style"`
+	Damage int    `json:"damage"`
+}
+
+// Style returns the player's combat style, defaulting to melee
+func (p *Player) Style() string {
+	if p.CombatStyle == "" {
+		return StyleMelee
+	}
+	return p.CombatStyle
+}
+
+// AvailableStyles returns the combat styles open to the player. Melee is
+// always available; ranged and magic need a bow or staff equipped.
+func (p *Player) AvailableStyles() []string {
+	styles := []string{StyleMelee}
+	if p.EquippedWeapon != nil && p.EquippedWeapon.Style != "" && p.EquippedWeapon.Style != StyleMelee {
+		styles = append(styles, p.EquippedWeapon.Style)
+	}
+	return styles
+}
+
+// SetCombatStyle switches the player to a combat style their weapon allows
+func (p *Player) SetCombatStyle(style string) error {
+	for _, available := range p.AvailableStyles() {
+		if available == style {
+			p.CombatStyle = style
+			return nil
+		}
+	}
+	if style != StyleMelee && style != StyleRanged && style != StyleMagic {
+		return fmt.Errorf("unknown combat style %q", style)
+	}
+	return fmt.Errorf("you need a %s weapon equipped to fight with %s", style, style)
+}
+
+// EquipWeapon moves a weapon from the inventory into the weapon slot,
+// returning any weapon already equipped to the inventory. The player switches
+// to the new weapon's combat style.
+func (p *Player) EquipWeapon(item InventoryItem) {
+	p.RemoveItemFromInventory(item.ID, 1)
+	p.UnequipWeapon()
+	item.Quantity = 1
+	p.EquippedWeapon = &item
+	if item.Style != "" {
+		p.CombatStyle = item.Style
+	}
+}
+
+// UnequipWeapon returns the equipped weapon to the inventory, falling back to melee
+func (p *Player) UnequipWeapon() {
+	if p.EquippedWeapon == nil {
+		return
+	}
+	p.AddItemToInventory(*p.EquippedWeapon)
+	p.EquippedWeapon = nil
+	p.CombatStyle = StyleMelee
+}
+
+// WearWeapon uses up one point of the equipped weapon's durability,
+// reporting whether that broke it
+func (p *Player) WearWeapon() bool {
+	weapon := p.EquippedWeapon
+	if weapon == nil || weapon.Stats.MaxDurability == 0 || weapon.Stats.Durability <= 0 {
+		return false
+	}
+	weapon.Stats.Durability--
+	return weapon.Broken()
+}
+
+// Combat triangle and weakness multipliers applied to damage after defense
+const (
+	TriangleAdvantage    = 1.25
+	TriangleDisadvantage = 0.8
+	WeaknessMultiplier   = 1.5
+)
+
+// Beats reports whether a combat style has the advantage over another in the
+// combat triangle: melee beats ranged, ranged beats magic and magic beats melee
+func Beats(style, other string) bool {
+	switch style {
+	case StyleMelee:
+		return other == StyleRanged
+	case StyleRanged:
+		return other == StyleMagic
+	case StyleMagic:
+		return other == StyleMelee
+	}
+	return false
+}
+
+// TriangleMultiplier returns the damage multiplier for an attacker's style against a defender's
+func TriangleMultiplier(attacker, defender string) float64 {
+	switch {
+	case Beats(attacker, defender):
+		return TriangleAdvantage
+	case Beats(defender, attacker):
+		return TriangleDisadvantage
+	}
+	return 1
+}
+
+// DefenseAgainst returns the enemy's defense against a damage type
+func (e Enemy) DefenseAgainst(damageType string) int {
+	if defense, ok := e.Defenses[damageType]; ok {
+		return defense
+	}
+	return e.Defense
+}
+
+// IsWeakTo reports whether a damage type deals extra damage to the enemy
+func (e Enemy) IsWeakTo(damageType string) bool {
+	for _, weakness := range e.Weaknesses {
+		if weakness == damageType {
+			return true
+		}
+	}
+	return false
+}
+
+// DamageMultiplier returns how much attacks in a combat style are scaled
+// against the enemy by the combat triangle and its weaknesses
+func (e Enemy) DamageMultiplier(style string) float64 {
+	multiplier := TriangleMultiplier(style, e.Style)
+	if e.IsWeakTo(DamageType(style)) {
+		multiplier *= WeaknessMultiplier
+	}
+	return multiplier
+}
+
+// StyleMatchup describes how well a combat style fares against an enemy
+type StyleMatchup struct {
+	Style      string  `json:"style"`
+	Defense    int     `json:"defense"`
+	Multiplier float64 `json:"multiplier"`
+	Weak       bool    `json:"weak"`
+}
+
+// Matchups returns how each combat style fares against the enemy
+func (e Enemy) Matchups() []StyleMatchup {
+	matchups := []StyleMatchup{}
+	for _, style := range []string{StyleMelee, StyleRanged, StyleMagic} {
+		damageType := DamageType(style)
+		matchups = append(matchups, StyleMatchup{
+			Style:      style,
+			Defense:    e.DefenseAgainst(damageType),
+			Multiplier: e.DamageMultiplier(style),
+			Weak:       e.IsWeakTo(damageType),
+		})
+	}
+	return matchups
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"math/rand"
+)
+
+// StationCookingRange is the crafting station type food is cooked on
+const StationCookingRange = "cooking_range"
+
+// CookingRecipe turns one raw ingredient into food. Below StopBurnLevel there
+// is a chance of burning it instead, falling from BurnChance at the recipe's
+// level to nothing at StopBurnLevel.
+type CookingRecipe struct {
+	ID            uint    `json:"id"`
+	Name          string  `json:"name"`
+	Level         int     `json:"level"`
+	Experience    int     `json:"experience"`
+	RawItemID     uint    `json:"rawItemId"`
+	CookedItemID  uint    `json:"cookedItemId"`
+	BurntItemID   uint    `json:"burntItemId"`
+	BurnChance    float64 `json:"burnChance"`
+	StopBurnLevel int     `json:"stopBurnLevel"`
+}
+
+// BurnChanceAt returns the chance of burning the food at the given cooking level
+func (r CookingRecipe) BurnChanceAt(level int) float64 {
+	if level >= r.StopBurnLevel || r.StopBurnLevel <= r.Level {
+		return 0
+	}
+	if level <= r.Level {
+		return r.BurnChance
+	}
+	progress := float64(level-r.Level) / float64(r.StopBurnLevel-r.Level)
+	return r.BurnChance * (1 - progress)
+}
+
+// Burns rolls whether one attempt at the recipe burns at the given cooking level
+func (r CookingRecipe) Burns(rng *rand.Rand, level int) bool {
+	return rng.Float64() < r.BurnChanceAt(level)
+}
@@ -0,0 +1,109 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// DeathConfig controls what a player loses when they die. Dropped items are
+// left in a gravestone where the player died, and can be retrieved until it
+// crumbles after GraveMinutes.
+type DeathConfig struct {
+	RespawnHealthPercent int      `json:"respawnHealthPercent"`
+	GoldLossPercent      int      `json:"goldLossPercent"`
+	DropItems            bool     `json:"dropItems"`
+	ProtectedTypes       []string `json:"protectedTypes"`
+	GraveMinutes         int      `json:"graveMinutes"`
+}
+
+// DefaultDeathConfig respawns players at half health, takes a tenth of their
+// gold and drops everything but tools and capes for fifteen minutes
+var DefaultDeathConfig = DeathConfig{
+	RespawnHealthPercent: 50,
+	GoldLossPercent:      10,
+	DropItems:            true,
+	ProtectedTypes:       []string{"tool", "cape"},
+	GraveMinutes:         15,
+}
+
+// Validate reports whether the config describes a usable death penalty
+func (c DeathConfig) Validate() error {
+	if c.RespawnHealthPercent < 1 || c.RespawnHealthPercent > 100 {
+		return fmt.Errorf("respawnHealthPercent must be between 1 and 100")
+	}
+	if c.GoldLossPercent < 0 || c.GoldLossPercent > 100 {
+		return fmt.Errorf("goldLossPercent must be between 0 and 100")
+	}
+	if c.GraveMinutes < 1 {
+		return fmt.Errorf("graveMinutes must be at least 1")
+	}
+	return nil
+}
+
+// GraveDuration returns how long a gravestone lasts
+func (c DeathConfig) GraveDuration() time.Duration {
+	return time.Duration(c.GraveMinutes) * time.Minute
+}
+
+// IsProtected reports whether the player keeps the item when they die
+func (c DeathConfig) IsProtected(item InventoryItem) bool {
+	if !c.DropItems {
+		return true
+	}
+	for _, itemType := range c.ProtectedTypes {
+		if item.Type == itemType {
+			return true
+		}
+	}
+	return false
+}
+
+// Gravestone holds the items a player dropped when they died
+type Gravestone struct {
+	ID        uint            `json:"id"`
+	PlayerID  uint            `json:"playerId"`
+	Location  string          `json:"location"`
+	Items     []InventoryItem `json:"items"`
+	DiedAt    time.Time       `json:"diedAt"`
+	ExpiresAt time.Time       `json:"expiresAt"`
+}
+
+// HasExpired reports whether the gravestone has crumbled and its items are lost
+func (g *Gravestone) HasExpired(now time.Time) bool {
+	return !now.Before(g.ExpiresAt)
+}
+
+// DeathRecord is one death of a player, kept for statistics
+type DeathRecord struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	PlayerID     uint      `json:"player_id"`
+	Cause        string    `json:"cause"`
+	Location     string    `json:"location"`
+	GoldLost     int       `json:"gold_lost"`
+	ItemsDropped int       `json:"items_dropped"`
+	DiedAt       time.Time `json:"died_at"`
+}
+
+// DropUnprotected removes every item the config doesn't protect from the
+// player's inventory and returns them
+func (p *Player) DropUnprotected(config DeathConfig) []InventoryItem {
+	kept := []InventoryItem{}
+	dropped := []InventoryItem{}
+	for _, item := range p.Inventory.Materials {
+		if config.IsProtected(item) {
+			kept = append(kept, item)
+		} else {
+			dropped = append(dropped, item)
+		}
+	}
+	p.Inventory.Materials = kept
+	return dropped
+}
+
+// Respawn brings a dead player back at the given location with a percentage
+// of their maximum health and no status effects
+func (p *Player) Respawn(location string, healthPercent int) {
+	p.Location = location
+	p.Health = max(1, p.MaxHealth*healthPercent/100)
+	p.StatusEffects = nil
+}
@@ -0,0 +1,161 @@
+package models
+
+import "time"
+
+// Dialogue condition types
+const (
+	// ConditionQuestState needs a quest to be in State: "none", "active" or "completed"
+	ConditionQuestState = "quest_state"
+	// ConditionSkillLevel needs Skill at Amount or higher
+	ConditionSkillLevel = "skill_level"
+	// ConditionHasItem needs Amount of ItemID in the inventory
+	ConditionHasItem = "has_item"
+)
+
+// Dialogue action types
+const (
+	// ActionStartQuest gives the player QuestID
+	ActionStartQuest = "start_quest"
+	// ActionOpenShop shows the player ShopID's wares
+	ActionOpenShop = "open_shop"
+	// ActionGiveItem gives the player Amount of ItemID
+	ActionGiveItem = "give_item"
+	// ActionCompleteQuest completes the active QuestID, handing in Amount of ItemID if set
+	ActionCompleteQuest = "complete_quest"
+)
+
+// Quest states as stored in PlayerQuest.Status, plus QuestNone for quests never started
+const (
+	QuestNone      = "none"
+	QuestActive    = "active"
+	QuestCompleted = "completed"
+)
+
+// DialogueCondition is something the player must satisfy to see a node or choice
+type DialogueCondition struct {
+	Type    string `json:"type"`
+	QuestID uint   `json:"questId,omitempty"`
+	State   string `json:"state,omitempty"`
+	Skill   string `json:"skill,omitempty"`
+	ItemID  uint   `json:"itemId,omitempty"`
+	Amount  int    `json:"amount,omitempty"`
+}
+
+// IsMet reports whether the player satisfies the condition
+func (c DialogueCondition) IsMet(p *Player) bool {
+	switch c.Type {
+	case ConditionQuestState:
+		return p.QuestStatus(c.QuestID) == c.State
+	case ConditionSkillLevel:
+		return p.SkillLevel(c.Skill) >= c.Amount
+	case ConditionHasItem:
+		return p.ItemQuantity(c.ItemID) >= c.Amount
+	}
+	return false
+}
+
+// DialogueAction is something that happens when the player picks a choice
+type DialogueAction struct {
+	Type    string `json:"type"`
+	QuestID uint   `json:"questId,omitempty"`
+	ShopID  uint   `json:"shopId,omitempty"`
+	ItemID  uint   `json:"itemId,omitempty"`
+	Amount  int    `json:"amount,omitempty"`
+}
+
+// DialogueChoice is a reply the player can give. Next is the node it leads
+// to; an empty Next ends the conversation.
+type DialogueChoice struct {
+	Text       string              `json:"text"`
+	Next       string              `json:"next,omitempty"`
+	Conditions []DialogueCondition `json:"conditions,omitempty"`
+	Actions    []DialogueAction    `json:"actions,omitempty"`
+}
+
+// DialogueNode is one thing an NPC says. If the player doesn't meet the
+// node's conditions the conversation moves on to Else instead.
+type DialogueNode struct {
+	ID         string              `json:"id"`
+	Text       string              `json:"text"`
+	Conditions []DialogueCondition `json:"conditions,omitempty"`
+	Else       string              `json:"else,omitempty"`
+	Choices    []DialogueChoice    `json:"choices"`
+}
+
+// NPC is a character the player can talk to. Every conversation begins at
+// the Start node.
+type NPC struct {
+	ID          uint                    `json:"id"`
+	Name        string                  `json:"name"`
+	Description string                  `json:"description"`
+	Location    string                  `json:"location"`
+	Start       string                  `json:"start"`
+	Nodes       map[string]DialogueNode `json:"-"`
+}
+
+// Conversation is where a player is in a dialogue with an NPC
+type Conversation struct {
+	PlayerID  uint      `json:"playerId"`
+	NPCID     uint      `json:"npcId"`
+	NodeID    string    `json:"nodeId"`
+	StartedAt time.Time `json:"startedAt"`
+}
+
+// MeetsConditions reports whether the player satisfies every condition
+func MeetsConditions(p *Player, conditions []DialogueCondition) bool {
+	for _, condition := range conditions {
+		if !condition.IsMet(p) {
+			return false
+		}
+	}
+	return true
+}
+
+// maxDialogueRedirects stops a badly authored tree of Else links from looping forever
+const maxDialogueRedirects = 16
+
+// Resolve returns the node the player actually reaches when heading for
+// nodeID, following Else links past nodes whose conditions they don't meet
+func (n *NPC) Resolve(p *Player, nodeID string) (DialogueNode, bool) {
+	for i := 0; i < maxDialogueRedirects; i++ {
+		node, ok := n.Nodes[nodeID]
+		if !ok {
+			return DialogueNode{}, false
+		}
+		if MeetsConditions(p, node.Conditions) {
+			return node, true
+		}
+		nodeID = node.Else
+	}
+	return DialogueNode{}, false
+}
+
+// QuestStatus returns whether the player has started or completed a quest
+func (p *Player) QuestStatus(questID uint) string {
+	for _, quest := range p.CompletedQuests {
+		if quest.QuestID == questID {
+			return QuestCompleted
+		}
+	}
+	for _, quest := range p.ActiveQuests {
+		if quest.QuestID == questID {
+			return QuestActive
+		}
+	}
+	return QuestNone
+}
+
+// CompleteQuest moves an active quest to the player's completed quests
+func (p *Player) CompleteQuest(questID uint, now time.Time) bool {
+	for i, quest := range p.ActiveQuests {
+		if quest.QuestID != questID {
+			continue
+		}
+		p.ActiveQuests = append(p.ActiveQuests[:i], p.ActiveQuests[i+1:]...)
+		quest.Status = QuestCompleted
+		quest.CompletedAt = now
+		p.CompletedQuests = append(p.CompletedQuests, quest)
+		return true
+	}
+	return false
+}
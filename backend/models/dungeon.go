@@ -0,0 +1,153 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// RoomType identifies what happens when a player enters a dungeon room
+type RoomType string
+
+const (
+	RoomCombat   RoomType = "combat"
+	RoomTreasure RoomType = "treasure"
+	RoomTrap     RoomType = "trap"
+	RoomRest     RoomType = "rest"
+	RoomBoss     RoomType = "boss"
+)
+
+// Dungeon run statuses
+const (
+	RunActive    = "active"
+	RunCompleted = "completed"
+	RunFled      = "fled"
+	RunDead      = "dead"
+)
+
+// DungeonRoom is a single step of a dungeon. Only the fields relevant to the
+// room's type are set. Exits lists the indexes of the rooms reachable from
+// this one; a room without exits leads to the next room in the list.
+type DungeonRoom struct {
+	Type        RoomType `json:"type"`
+	Description string   `json:"description"`
+	Exits       []int    `json:"exits,omitempty"`
+	Enemy       *Enemy   `json:"enemy,omitempty"`      // combat and boss rooms
+	Gold        int      `json:"gold,omitempty"`       // treasure rooms
+	Chest       string   `json:"chest,omitempty"`      // treasure rooms, names the loot table to roll
+	TrapDamage  int      `json:"trapDamage,omitempty"` // trap rooms
+	HealAmount  int      `json:"healAmount,omitempty"` // rest rooms, restores health and stamina
+}
+
+// IsEncounter reports whether the room must be cleared by combat before advancing
+func (r DungeonRoom) IsEncounter() bool {
+	return r.Type == RoomCombat || r.Type == RoomBoss
+}
+
+type Dungeon struct {
+	ID               uint          `json:"id"`
+	Name             string        `json:"name"`
+	Description      string        `json:"description"`
+	RecommendedLevel int           `json:"recommendedLevel"`
+	Rooms            []DungeonRoom `json:"rooms"`
+	Seed             int64         `json:"seed,omitempty"` // set for generated dungeons
+	Tier             int           `json:"tier,omitempty"`
+}
+
+// DungeonLoot is the reward accumulated over a run. It is only granted to the
+// player when the run is completed.
+type DungeonLoot struct {
+	Gold  int        `json:"gold"`
+	Items []LootDrop `json:"items"`
+}
+
+// DungeonRun tracks a player's progress through a dungeon
+type DungeonRun struct {
+	DungeonID   uint          `json:"dungeonId"`
+	DungeonName string        `json:"dungeonName"`
+	PlayerID    uint          `json:"playerId"`
+	Status      string        `json:"status"`
+	RoomIndex   int           `json:"roomIndex"`
+	Rooms       []DungeonRoom `json:"rooms"`
+	Enemy       *Enemy        `json:"enemy,omitempty"` // live copy of the current room's enemy
+	RoomCleared bool          `json:"roomCleared"`
+	Loot        DungeonLoot   `json:"loot"`
+	StartedAt   time.Time     `json:"startedAt"`
+	EndedAt     time.Time     `json:"endedAt"`
+	// Experience is granted as each enemy falls, so it is kept even if the run fails
+	Experience int `json:"experience"`
+}
+
+// NewDungeonRun starts a run at the first room of the dungeon
+func NewDungeonRun(dungeon Dungeon, playerID uint) *DungeonRun {
+	run := &DungeonRun{
+		DungeonID:   dungeon.ID,
+		DungeonName: dungeon.Name,
+		PlayerID:    playerID,
+		Status:      RunActive,
+		Rooms:       dungeon.Rooms,
+		StartedAt:   time.Now(),
+	}
+	run.enterRoom(0)
+	return run
+}
+
+// CurrentRoom returns the room the player is standing in
+func (r *DungeonRun) CurrentRoom() DungeonRoom {
+	return r.Rooms[r.RoomIndex]
+}
+
+// NextRooms returns the indexes of the rooms the player can move on to
+func (r *DungeonRun) NextRooms() []int {
+	room := r.CurrentRoom()
+	if len(room.Exits) > 0 {
+		return room.Exits
+	}
+	if r.RoomIndex+1 < len(r.Rooms) {
+		return []int{r.RoomIndex + 1}
+	}
+	return nil
+}
+
+// IsLastRoom reports whether the player is in the final room of the dungeon
+func (r *DungeonRun) IsLastRoom() bool {
+	return len(r.NextRooms()) == 0
+}
+
+// Advance moves the run into the given room, which must be reachable from the
+// current one. Passing -1 takes the first exit.
+func (r *DungeonRun) Advance(next int) error {
+	exits := r.NextRooms()
+	if len(exits) == 0 {
+		return fmt.Errorf("there are no more rooms to explore")
+	}
+	if next == -1 {
+		next = exits[0]
+	}
+	for _, exit := range exits {
+		if exit == next {
+			r.enterRoom(next)
+			return nil
+		}
+	}
+	return fmt.Errorf("room %d cannot be reached from here", next)
+}
+
+// End finishes the run with the given status
+func (r *DungeonRun) End(status string) {
+	r.Status = status
+	r.Enemy = nil
+	r.EndedAt = time.Now()
+}
+
+func (r *DungeonRun) enterRoom(index int) {
+	r.RoomIndex = index
+	r.RoomCleared = false
+	r.Enemy = nil
+
+	room := r.Rooms[index]
+	if room.IsEncounter() && room.Enemy != nil {
+		// Copy the enemy so the dungeon definition is never mutated by combat
+		enemy := *room.Enemy
+		r.Enemy = &enemy
+	}
+}
@@ -0,0 +1,193 @@
+package models
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+)
+
+// MaxDungeonTier is the hardest tier a dungeon can be generated at
+const MaxDungeonTier = 20
+
+// Weighted odds of each room type appearing between the entrance and the boss
+var generatedRoomWeights = []struct {
+	Type   RoomType
+	Weight int
+}{
+	{RoomCombat, 50},
+	{RoomTreasure, 20},
+	{RoomTrap, 15},
+	{RoomRest, 15},
+}
+
+// GenerateDungeon builds a dungeon layout from a seed and difficulty tier.
+// The same seed, tier and mob list always produce the same dungeon, so mobs
+// must be passed in a stable order.
+//
+// The layout is a layered graph: a single entrance, a number of middle layers
+// of one or two rooms each that scales with the tier, and a boss room at the
+// end. Every room links to at least one room in the following layer.
+func GenerateDungeon(seed int64, tier int, mobs []Enemy) (Dungeon, error) {
+	if tier < 1 || tier > MaxDungeonTier {
+		return Dungeon{}, fmt.Errorf("tier must be between 1 and %d", MaxDungeonTier)
+	}
+	band := mobsInLevelBand(mobs, tier)
+	if len(band) == 0 {
+		return Dungeon{}, fmt.Errorf("no mobs available for tier %d", tier)
+	}
+
+	rng := rand.New(rand.NewSource(seed))
+
+	// Lay out the layers first so exits can point at real indexes
+	layerSizes := []int{1}
+	for i := 0; i < 3+tier; i++ {
+		layerSizes = append(layerSizes, 1+rng.Intn(2))
+	}
+	layerSizes = append(layerSizes, 1)
+
+	var rooms []DungeonRoom
+	var layers [][]int
+	for layer, size := range layerSizes {
+		var indexes []int
+		for i := 0; i < size; i++ {
+			var room DungeonRoom
+			switch layer {
+			case 0:
+				room = generateCombatRoom(rng, band, tier)
+			case len(layerSizes) - 1:
+				room = generateBossRoom(band, tier)
+			default:
+				room = generateRoom(rng, band, tier)
+			}
+			indexes = append(indexes, len(rooms))
+			rooms = append(rooms, room)
+		}
+		layers = append(layers, indexes)
+	}
+
+	// Connect each layer to the next, making sure every room is reachable
+	for layer := 0; layer < len(layers)-1; layer++ {
+		next := layers[layer+1]
+		for _, index := range layers[layer] {
+			rooms[index].Exits = append(rooms[index].Exits, next[rng.Intn(len(next))])
+		}
+		for _, target := range next {
+			if !hasEntrance(rooms, layers[layer], target) {
+				from := layers[layer][rng.Intn(len(layers[layer]))]
+				rooms[from].Exits = append(rooms[from].Exits, target)
+			}
+		}
+		for _, index := range layers[layer] {
+			sort.Ints(rooms[index].Exits)
+		}
+	}
+
+	return Dungeon{
+		Name:             fmt.Sprintf("Tier %d Depths #%d", tier, seed),
+		Description:      "A shifting labyrinth that is never quite the same twice",
+		RecommendedLevel: band[0].Level,
+		Rooms:            rooms,
+		Seed:             seed,
+		Tier:             tier,
+	}, nil
+}
+
+// mobsInLevelBand returns the mobs whose level suits the tier, falling back to
+// the highest level mobs available when the band is empty
+func mobsInLevelBand(mobs []Enemy, tier int) []Enemy {
+	minLevel, maxLevel := tier, tier+1
+
+	var band []Enemy
+	highest := 0
+	for _, mob := range mobs {
+		if mob.Level >= minLevel && mob.Level <= maxLevel {
+			band = append(band, mob)
+		}
+		if mob.Level > highest {
+			highest = mob.Level
+		}
+	}
+	if len(band) > 0 {
+		return band
+	}
+
+	for _, mob := range mobs {
+		if mob.Level == highest {
+			band = append(band, mob)
+		}
+	}
+	return band
+}
+
+func hasEntrance(rooms []DungeonRoom, layer []int, target int) bool {
+	for _, index := range layer {
+		for _, exit := range rooms[index].Exits {
+			if exit == target {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func generateRoom(rng *rand.Rand, band []Enemy, tier int) DungeonRoom {
+	total := 0
+	for _, w := range generatedRoomWeights {
+		total += w.Weight
+	}
+	roll := rng.Intn(total)
+
+	roomType := RoomCombat
+	for _, w := range generatedRoomWeights {
+		if roll < w.Weight {
+			roomType = w.Type
+			break
+		}
+		roll -= w.Weight
+	}
+
+	switch roomType {
+	case RoomTreasure:
+		gold := (15 + rng.Intn(20)) * tier
+		return DungeonRoom{Type: RoomTreasure, Description: "A forgotten stash glints in the dark", Gold: gold, Chest: "Dungeon Chest"}
+	case RoomTrap:
+		damage := (5 + rng.Intn(10)) * tier
+		return DungeonRoom{Type: RoomTrap, Description: "Pressure plates line the floor", TrapDamage: damage}
+	case RoomRest:
+		return DungeonRoom{Type: RoomRest, Description: "A quiet chamber untouched by monsters", HealAmount: 20 + 10*tier}
+	default:
+		return generateCombatRoom(rng, band, tier)
+	}
+}
+
+func generateCombatRoom(rng *rand.Rand, band []Enemy, tier int) DungeonRoom {
+	enemy := band[rng.Intn(len(band))]
+	return DungeonRoom{
+		Type:        RoomCombat,
+		Description: fmt.Sprintf("A %s prowls the chamber", enemy.Name),
+		Enemy:       &enemy,
+	}
+}
+
+// generateBossRoom promotes the strongest mob in the band to a boss
+func generateBossRoom(band []Enemy, tier int) DungeonRoom {
+	boss := band[0]
+	for _, mob := range band[1:] {
+		if mob.Level > boss.Level {
+			boss = mob
+		}
+	}
+
+	boss.Name = fmt.Sprintf("%s Overlord", boss.Name)
+	boss.Level += tier
+	boss.MaxHealth *= 2
+	boss.Health = boss.MaxHealth
+	boss.MaxDamage = boss.MaxDamage * 3 / 2
+	boss.Defense += tier
+
+	return DungeonRoom{
+		Type:        RoomBoss,
+		Description: fmt.Sprintf("The %s guards the way out", boss.Name),
+		Enemy:       &boss,
+	}
+}
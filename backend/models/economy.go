@@ -0,0 +1,145 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// Ledger sources describe where gold or items came from or went to
+const (
+	SourceMobDrop     = "mob_drop"
+	SourceDungeonLoot = "dungeon_loot"
+	SourceShopBuy     = "shop_buy"
+	SourceShopSell    = "shop_sell"
+	SourceTrade       = "trade"
+	SourceMarketTrade = "market_trade"
+	SourceMarketFee   = "market_fee"
+	SourceRespec      = "respec"
+	SourceFishing     = "fishing"
+	SourceFarming     = "farming"
+	SourceMining      = "mining"
+	SourceWoodcutting = "woodcutting"
+	SourceAchievement = "achievement"
+	SourceCape        = "cape"
+	SourceNPC         = "npc"
+	SourceDeath       = "death"
+	SourceInn         = "inn"
+	SourceAmmo        = "ammo"
+	SourceSpell       = "spell"
+	SourceRepair      = "repair"
+)
+
+// LedgerEntry records gold or items entering or leaving a player. Positive
+// amounts were received and negative amounts were spent or given away.
+type LedgerEntry struct {
+	Time         time.Time `json:"time"`
+	PlayerID     uint      `json:"playerId"`
+	Source       string    `json:"source"`
+	Gold         int       `json:"gold"`
+	ItemID       uint      `json:"itemId,omitempty"`
+	ItemQuantity int       `json:"itemQuantity,omitempty"`
+}
+
+// SourceFlow totals the gold and items moved by one source. GoldIn is gold
+// received by players and GoldOut gold taken from them, so a positive net is
+// a faucet and a negative net a sink. Player to player transfers net to zero.
+type SourceFlow struct {
+	Source   string `json:"source"`
+	GoldIn   int    `json:"goldIn"`
+	GoldOut  int    `json:"goldOut"`
+	NetGold  int    `json:"netGold"`
+	ItemsIn  int    `json:"itemsIn"`
+	ItemsOut int    `json:"itemsOut"`
+}
+
+// FlowBucket is the per-source flow over one slice of a report window
+type FlowBucket struct {
+	Start   time.Time     `json:"start"`
+	NetGold int           `json:"netGold"`
+	Flows   []*SourceFlow `json:"flows"`
+}
+
+// Ledger is an append-only log of every gold and item movement. Entries are
+// kept in time order so a report window can be found without scanning the
+// whole log.
+type Ledger struct {
+	Entries []LedgerEntry `json:"entries"`
+}
+
+// Record adds an entry in time order, skipping entries that move nothing
+func (l *Ledger) Record(entry LedgerEntry) {
+	if entry.Gold == 0 && entry.ItemQuantity == 0 {
+		return
+	}
+	i := sort.Search(len(l.Entries), func(i int) bool { return l.Entries[i].Time.After(entry.Time) })
+	l.Entries = append(l.Entries, LedgerEntry{})
+	copy(l.Entries[i+1:], l.Entries[i:])
+	l.Entries[i] = entry
+}
+
+// Flows totals each source's movements between from (inclusive) and to (exclusive)
+func (l *Ledger) Flows(from, to time.Time) []*SourceFlow {
+	return flowsOf(l.between(from, to))
+}
+
+// Buckets splits the window into consecutive buckets of the given size and
+// totals each source's movements within each one
+func (l *Ledger) Buckets(from, to time.Time, size time.Duration) []FlowBucket {
+	var buckets []FlowBucket
+	if size <= 0 {
+		return buckets
+	}
+
+	for start := from; start.Before(to); start = start.Add(size) {
+		end := start.Add(size)
+		if end.After(to) {
+			end = to
+		}
+		flows := flowsOf(l.between(start, end))
+
+		net := 0
+		for _, flow := range flows {
+			net += flow.NetGold
+		}
+		buckets = append(buckets, FlowBucket{Start: start, NetGold: net, Flows: flows})
+	}
+	return buckets
+}
+
+// between returns the entries from from (inclusive) to to (exclusive)
+func (l *Ledger) between(from, to time.Time) []LedgerEntry {
+	start := sort.Search(len(l.Entries), func(i int) bool { return !l.Entries[i].Time.Before(from) })
+	end := sort.Search(len(l.Entries), func(i int) bool { return !l.Entries[i].Time.Before(to) })
+	if end < start {
+		return nil
+	}
+	return l.Entries[start:end]
+}
+
+func flowsOf(entries []LedgerEntry) []*SourceFlow {
+	flows := []*SourceFlow{}
+	bySource := map[string]*SourceFlow{}
+
+	for _, entry := range entries {
+		flow, ok := bySource[entry.Source]
+		if !ok {
+			flow = &SourceFlow{Source: entry.Source}
+			bySource[entry.Source] = flow
+			flows = append(flows, flow)
+		}
+
+		if entry.Gold > 0 {
+			flow.GoldIn += entry.Gold
+		} else {
+			flow.GoldOut -= entry.Gold
+		}
+		flow.NetGold += entry.Gold
+
+		if entry.ItemQuantity > 0 {
+			flow.ItemsIn += entry.ItemQuantity
+		} else {
+			flow.ItemsOut -= entry.ItemQuantity
+		}
+	}
+	return flows
+}
@@ -0,0 +1,151 @@
+package models
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+)
+
+// Farm plot statuses
+const (
+	PlotEmpty    = "empty"
+	PlotGrowing  = "growing"
+	PlotGrown    = "grown"
+	PlotDiseased = "diseased"
+	PlotDead     = "dead"
+)
+
+// compostYieldBonus is the extra produce harvested from a composted plot
+const compostYieldBonus = 2
+
+// Crop is something that can be grown from a seed. It passes through Stages
+// growth stages of StageDuration each, and a stage that starts without the
+// plot being tended may leave the crop diseased.
+type Crop struct {
+	ID                uint          `json:"id"`
+	Name              string        `json:"name"`
+	SeedID            uint          `json:"seedId"`
+	ProduceID         uint          `json:"produceId"`
+	Level             int           `json:"level"`
+	PlantExperience   int           `json:"plantExperience"`
+	HarvestExperience int           `json:"harvestExperience"`
+	Stages            int           `json:"stages"`
+	StageDuration     time.Duration `json:"stageDuration"`
+	BaseYield         int           `json:"baseYield"`
+	DiseaseChance     float64       `json:"diseaseChance"`
+}
+
+// FarmPlot is a patch of land owned by a player. Growth is worked out lazily
+// from the time the current stage started whenever the plot is looked at.
+type FarmPlot struct {
+	ID               uint      `json:"id"`
+	PlayerID         uint      `json:"playerId"`
+	Location         string    `json:"location"`
+	Status           string    `json:"status"`
+	CropID           uint      `json:"cropId,omitempty"`
+	Stage            int       `json:"stage"`
+	StageStartedAt   time.Time `json:"stageStartedAt"`
+	LastTended       time.Time `json:"lastTended"`
+	DiseasedAt       time.Time `json:"diseasedAt"`
+	Composted        bool      `json:"composted"`
+	Waterings        int       `json:"waterings"`
+	LastWateredStage int       `json:"-"`
+}
+
+// Plant sows a crop in an empty plot
+func (p *FarmPlot) Plant(crop Crop, now time.Time) error {
+	if p.Status != PlotEmpty {
+		return fmt.Errorf("plot is %s", p.Status)
+	}
+	p.Status = PlotGrowing
+	p.CropID = crop.ID
+	p.Stage = 0
+	p.StageStartedAt = now
+	p.LastTended = now
+	p.Waterings = 0
+	p.LastWateredStage = -1
+	return nil
+}
+
+// Update advances the crop through every growth stage that has finished
+// since it was last updated, rolling for disease on each stage that went
+// untended. A diseased crop that isn't cured within a stage dies.
+func (p *FarmPlot) Update(crop Crop, now time.Time, rng *rand.Rand) {
+	for p.Status == PlotGrowing && !now.Before(p.StageStartedAt.Add(crop.StageDuration)) {
+		stageEnd := p.StageStartedAt.Add(crop.StageDuration)
+		if p.LastTended.Before(p.StageStartedAt) && rng.Float64() < crop.DiseaseChance {
+			p.Status = PlotDiseased
+			p.DiseasedAt = stageEnd
+			break
+		}
+
+		p.Stage++
+		p.StageStartedAt = stageEnd
+		if p.Stage >= crop.Stages {
+			p.Status = PlotGrown
+		}
+	}
+
+	if p.Status == PlotDiseased && !now.Before(p.DiseasedAt.Add(crop.StageDuration)) {
+		p.Status = PlotDead
+	}
+}
+
+// Water tends a growing crop, adding to the yield once per growth stage
+func (p *FarmPlot) Water(now time.Time) error {
+	if p.Status != PlotGrowing {
+		return fmt.Errorf("only growing crops can be watered")
+	}
+	p.LastTended = now
+	if p.LastWateredStage != p.Stage {
+		p.LastWateredStage = p.Stage
+		p.Waterings++
+	}
+	return nil
+}
+
+// Compost enriches an empty or growing plot, tending it and raising the yield
+func (p *FarmPlot) Compost(now time.Time) error {
+	if p.Status != PlotEmpty && p.Status != PlotGrowing {
+		return fmt.Errorf("plot is %s", p.Status)
+	}
+	if p.Composted {
+		return fmt.Errorf("plot is already composted")
+	}
+	p.Composted = true
+	p.LastTended = now
+	return nil
+}
+
+// Cure treats a diseased crop so it carries on growing from its current stage
+func (p *FarmPlot) Cure(now time.Time) error {
+	if p.Status != PlotDiseased {
+		return fmt.Errorf("plot is not diseased")
+	}
+	p.Status = PlotGrowing
+	p.StageStartedAt = now
+	p.LastTended = now
+	p.DiseasedAt = time.Time{}
+	return nil
+}
+
+// Yield returns how much produce the grown crop will give
+func (p *FarmPlot) Yield(crop Crop) int {
+	yield := crop.BaseYield + p.Waterings
+	if p.Composted {
+		yield += compostYieldBonus
+	}
+	return yield
+}
+
+// Clear empties the plot, dropping whatever was in it
+func (p *FarmPlot) Clear() {
+	p.Status = PlotEmpty
+	p.CropID = 0
+	p.Stage = 0
+	p.StageStartedAt = time.Time{}
+	p.DiseasedAt = time.Time{}
+	p.Composted = false
+	p.Waterings = 0
+	p.LastWateredStage = -1
+}
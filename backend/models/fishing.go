@@ -0,0 +1,80 @@
+package models
+
+import (
+	"math/rand"
+	"time"
+)
+
+// FishCatch is a fish (or treasure) that can be caught at a fishing spot.
+// Catches above the player's fishing level can't be caught, and a catch
+// becomes more common the further the player's level is above its own.
+type FishCatch struct {
+	LootEntry
+	Level      int `json:"level"`
+	Experience int `json:"experience"`
+}
+
+// FishingSpot is a place to fish at one of the world's locations
+type FishingSpot struct {
+	ID          uint          `json:"id"`
+	Name        string        `json:"name"`
+	Location    string        `json:"location"`
+	Level       int           `json:"level"`
+	ToolID      uint          `json:"toolId"`
+	BaitID      uint          `json:"baitId,omitempty"`
+	Duration    time.Duration `json:"duration"`
+	Catches     []FishCatch   `json:"catches"`
+	RareCatches []FishCatch   `json:"rareCatches"`
+	RareChance  int           `json:"rareChance"`
+}
+
+// levelWeightBonus is the extra weight a catch gets per fishing level above its requirement
+const levelWeightBonus = 2
+
+// Roll picks what the player catches at their fishing level. A rare catch is
+// tried first with a 1 in RareChance chance, falling back to a normal catch.
+func (s FishingSpot) Roll(rng *rand.Rand, level int) (FishCatch, LootDrop, bool) {
+	if len(s.RareCatches) > 0 && s.RareChance > 0 && rng.Intn(s.RareChance) == 0 {
+		if catch, drop, ok := rollCatch(rng, s.RareCatches, level); ok {
+			return catch, drop, true
+		}
+	}
+	return rollCatch(rng, s.Catches, level)
+}
+
+// AvailableCatches returns the normal catches the player's level allows with their chances
+func (s FishingSpot) AvailableCatches(level int) []DropChance {
+	_, entries := catchWeights(s.Catches, level)
+	return chances(entries, 0, 1)
+}
+
+func rollCatch(rng *rand.Rand, catches []FishCatch, level int) (FishCatch, LootDrop, bool) {
+	available, entries := catchWeights(catches, level)
+	entry, ok := pickWeighted(rng, entries, 0)
+	if !ok {
+		return FishCatch{}, LootDrop{}, false
+	}
+	for _, catch := range available {
+		if catch.Item.ID == entry.Item.ID {
+			return catch, entry.drop(rng), true
+		}
+	}
+	return FishCatch{}, LootDrop{}, false
+}
+
+// catchWeights returns the catches the level allows and their loot entries
+// with weights adjusted for the player's level
+func catchWeights(catches []FishCatch, level int) ([]FishCatch, []LootEntry) {
+	var available []FishCatch
+	var entries []LootEntry
+	for _, catch := range catches {
+		if level < catch.Level {
+			continue
+		}
+		entry := catch.LootEntry
+		entry.Weight += (level - catch.Level) * levelWeightBonus
+		available = append(available, catch)
+		entries = append(entries, entry)
+	}
+	return available, entries
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+)
+
+// GatheringAction is a timed skilling action a player has started at a
+// resource, such as casting a line at a fishing spot. Its result is rolled
+// once the action is collected after it finishes.
+type GatheringAction struct {
+	PlayerID  uint          `json:"playerId"`
+	Skill     string        `json:"skill"`
+	SourceID  uint          `json:"sourceId"`
+	Source    string        `json:"source"`
+	StartedAt time.Time     `json:"startedAt"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// FinishesAt returns when the action completes
+func (a GatheringAction) FinishesAt() time.Time {
+	return a.StartedAt.Add(a.Duration)
+}
+
+// IsDone reports whether the action has finished by the given time
+func (a GatheringAction) IsDone(now time.Time) bool {
+	return !now.Before(a.FinishesAt())
+}
+
+// Remaining returns how long is left before the action finishes
+func (a GatheringAction) Remaining(now time.Time) time.Duration {
+	if a.IsDone(now) {
+		return 0
+	}
+	return a.FinishesAt().Sub(now)
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// Idle action types
+const (
+	IdleFish   = "fish"
+	IdleGather = "gather"
+	IdleCraft  = "craft"
+	IdleCook   = "cook"
+	IdleFight  = "fight"
+)
+
+// Idle action statuses
+const (
+	IdleQueued    = "queued"
+	IdleCompleted = "completed"
+	IdleStopped   = "stopped"
+	IdleCancelled = "cancelled"
+)
+
+// IdleAction is a repeated action a player has queued to run on the server.
+// It repeats Repeat times, or until it can't carry on when Repeat is zero.
+// Fights also stop once the player's health drops below StopBelowHealth
+// percent.
+type IdleAction struct {
+	ID              uint      `json:"id"`
+	PlayerID        uint      `json:"playerId"`
+	Type            string    `json:"type"`
+	TargetID        uint      `json:"targetId,omitempty"`
+	Target          string    `json:"target,omitempty"`
+	StationID       uint      `json:"stationId,omitempty"`
+	Repeat          int       `json:"repeat"`
+	StopBelowHealth int       `json:"stopBelowHealth,omitempty"`
+	Completed       int       `json:"completed"`
+	Status          string    `json:"status"`
+	StopReason      string    `json:"stopReason,omitempty"`
+	QueuedAt        time.Time `json:"queuedAt"`
+	NextAt          time.Time `json:"nextAt"`
+}
+
+// IsFinished reports whether the action has done all of its repetitions
+func (a *IdleAction) IsFinished() bool {
+	return a.Repeat > 0 && a.Completed >= a.Repeat
+}
+
+// PlayerSnapshot captures the parts of a player that idle actions change, so
+// the difference can be reported afterwards
+type PlayerSnapshot struct {
+	Gold   int
+	Level  int
+	Skills []SkillProgress
+	Items  map[uint]InventoryItem
+}
+
+// Snapshot records the player's current gold, levels, experience and items
+func (p *Player) Snapshot() PlayerSnapshot {
+	items := map[uint]InventoryItem{}
+	for _, item := range p.Inventory.Materials {
+		items[item.ID] = item
+	}
+	return PlayerSnapshot{
+		Gold:   p.Gold,
+		Level:  p.Level,
+		Skills: p.SkillProgress(),
+		Items:  items,
+	}
+}
+
+// AwaySummary adds up everything idle actions did for a player while they were offline
+type AwaySummary struct {
+	Since       time.Time       `json:"since"`
+	Until       time.Time       `json:"until"`
+	Repetitions int             `json:"repetitions"`
+	Gold        int             `json:"gold"`
+	Levels      int             `json:"levels"`
+	Experience  map[string]int  `json:"experience"`
+	SkillLevels map[string]int  `json:"skillLevels"`
+	ItemsGained []InventoryItem `json:"itemsGained"`
+	ItemsUsed   []InventoryItem `json:"itemsUsed"`
+	Stopped     []string        `json:"stopped"`
+}
+
+// NewAwaySummary starts an empty summary from the given time
+func NewAwaySummary(since time.Time) *AwaySummary {
+	return &AwaySummary{
+		Since:       since,
+		Experience:  map[string]int{},
+		SkillLevels: map[string]int{},
+	}
+}
+
+// Record adds the change between two snapshots of the player to the summary
+func (s *AwaySummary) Record(before, after PlayerSnapshot) {
+	s.Gold += after.Gold - before.Gold
+	s.Levels += after.Level - before.Level
+
+	for i, skill := range after.Skills {
+		if gained := skill.Experience - before.Skills[i].Experience; gained > 0 {
+			s.Experience[skill.Skill] += gained
+		}
+		if levels := skill.Level - before.Skills[i].Level; levels > 0 {
+			s.SkillLevels[skill.Skill] += levels
+		}
+	}
+
+	ids := map[uint]bool{}
+	for id := range before.Items {
+		ids[id] = true
+	}
+	for id := range after.Items {
+		ids[id] = true
+	}
+	for id := range ids {
+		item, ok := after.Items[id]
+		if !ok {
+			item = before.Items[id]
+		}
+		change := after.Items[id].Quantity - before.Items[id].Quantity
+		if change > 0 {
+			s.ItemsGained = addQuantity(s.ItemsGained, item, change)
+		} else if change < 0 {
+			s.ItemsUsed = addQuantity(s.ItemsUsed, item, -change)
+		}
+	}
+}
+
+// addQuantity adds a quantity of an item to a list, stacking it onto an
+// existing entry and keeping the list ordered by item ID
+func addQuantity(items []InventoryItem, item InventoryItem, quantity int) []InventoryItem {
+	for i := range items {
+		if items[i].ID == item.ID {
+			items[i].Quantity += quantity
+			return items
+		}
+	}
+	item.Quantity = quantity
+	items = append(items, item)
+	sort.Slice(items, func(i, j int) bool { return items[i].ID < items[j].ID })
+	return items
+}
@@ -0,0 +1,112 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// LeaderboardEntry is one player's standing on a leaderboard. Players are
+// ranked by Score, then Tiebreak, highest first, and then by who reached
+// their score first.
+type LeaderboardEntry struct {
+	PlayerID   uint      `json:"playerId"`
+	Name       string    `json:"name"`
+	Score      int64     `json:"score"`
+	Tiebreak   int64     `json:"-"`
+	AchievedAt time.Time `json:"achievedAt"`
+}
+
+// RankedEntry is a leaderboard entry with its position, starting from 1
+type RankedEntry struct {
+	Rank int `json:"rank"`
+	LeaderboardEntry
+}
+
+// ranksAbove reports whether entry a is ranked above entry b
+func (a LeaderboardEntry) ranksAbove(b LeaderboardEntry) bool {
+	if a.Score != b.Score {
+		return a.Score > b.Score
+	}
+	if a.Tiebreak != b.Tiebreak {
+		return a.Tiebreak > b.Tiebreak
+	}
+	if !a.AchievedAt.Equal(b.AchievedAt) {
+		return a.AchievedAt.Before(b.AchievedAt)
+	}
+	return a.PlayerID < b.PlayerID
+}
+
+// Leaderboard keeps entries sorted as they are submitted, so pages and
+// ranks can be read without sorting or scanning every player
+type Leaderboard struct {
+	Category string
+	entries  []LeaderboardEntry
+	byPlayer map[uint]LeaderboardEntry
+}
+
+// NewLeaderboard returns an empty leaderboard for a category
+func NewLeaderboard(category string) *Leaderboard {
+	return &Leaderboard{Category: category, byPlayer: map[uint]LeaderboardEntry{}}
+}
+
+// Submit records a player's score. Submitting an unchanged score keeps the
+// time it was first reached, so earlier players stay ahead on ties.
+func (l *Leaderboard) Submit(entry LeaderboardEntry) {
+	if current, ok := l.byPlayer[entry.PlayerID]; ok {
+		if current.Score == entry.Score && current.Tiebreak == entry.Tiebreak && current.Name == entry.Name {
+			return
+		}
+		l.remove(current)
+	}
+
+	i := l.position(entry)
+	l.entries = append(l.entries, LeaderboardEntry{})
+	copy(l.entries[i+1:], l.entries[i:])
+	l.entries[i] = entry
+	l.byPlayer[entry.PlayerID] = entry
+}
+
+// Remove takes a player off the leaderboard
+func (l *Leaderboard) Remove(playerID uint) {
+	if current, ok := l.byPlayer[playerID]; ok {
+		l.remove(current)
+	}
+}
+
+func (l *Leaderboard) remove(entry LeaderboardEntry) {
+	i := l.position(entry)
+	l.entries = append(l.entries[:i], l.entries[i+1:]...)
+	delete(l.byPlayer, entry.PlayerID)
+}
+
+// position returns where the entry belongs in the sorted entries
+func (l *Leaderboard) position(entry LeaderboardEntry) int {
+	return sort.Search(len(l.entries), func(i int) bool {
+		return !l.entries[i].ranksAbove(entry)
+	})
+}
+
+// Len returns how many players are on the leaderboard
+func (l *Leaderboard) Len() int {
+	return len(l.entries)
+}
+
+// Page returns up to limit ranked entries starting after offset. A negative
+// offset starts from the top.
+func (l *Leaderboard) Page(offset, limit int) []RankedEntry {
+	page := []RankedEntry{}
+	offset = max(offset, 0)
+	for i := offset; i < len(l.entries) && i < offset+limit; i++ {
+		page = append(page, RankedEntry{Rank: i + 1, LeaderboardEntry: l.entries[i]})
+	}
+	return page
+}
+
+// Rank returns the player's ranked entry, or false if they aren't on the leaderboard
+func (l *Leaderboard) Rank(playerID uint) (RankedEntry, bool) {
+	entry, ok := l.byPlayer[playerID]
+	if !ok {
+		return RankedEntry{}, false
+	}
+	return RankedEntry{Rank: l.position(entry) + 1, LeaderboardEntry: entry}, true
+}
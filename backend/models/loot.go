@@ -0,0 +1,137 @@
+package models
+
+import (
+	"math/rand"
+)
+
+// Rarity describes how uncommon a drop is
+type Rarity string
+
+const (
+	RarityCommon    Rarity = "common"
+	RarityUncommon  Rarity = "uncommon"
+	RarityRare      Rarity = "rare"
+	RarityEpic      Rarity = "epic"
+	RarityLegendary Rarity = "legendary"
+)
+
+// LootEntry is one possible drop in a loot table. Weight only matters for
+// weighted rolls; guaranteed entries always drop.
+type LootEntry struct {
+	Item        InventoryItem `json:"item"`
+	Weight      int           `json:"weight"`
+	MinQuantity int           `json:"minQuantity"`
+	MaxQuantity int           `json:"maxQuantity"`
+	Rarity      Rarity        `json:"rarity"`
+}
+
+// LootTable describes everything a mob or chest can drop. Each roll picks one
+// weighted entry, where NothingWeight is the weight of dropping nothing. The
+// rare drop table is rolled separately with a 1 in RareTableChance chance.
+type LootTable struct {
+	Guaranteed      []LootEntry `json:"guaranteed"`
+	Entries         []LootEntry `json:"entries"`
+	NothingWeight   int         `json:"nothingWeight"`
+	Rolls           int         `json:"rolls"`
+	RareTable       []LootEntry `json:"rareTable"`
+	RareTableChance int         `json:"rareTableChance"`
+}
+
+// LootDrop is an item produced by rolling a loot table
+type LootDrop struct {
+	Item   InventoryItem `json:"item"`
+	Rarity Rarity        `json:"rarity"`
+}
+
+// DropChance is a loot entry together with its chance of dropping per roll
+type DropChance struct {
+	LootEntry
+	Chance float64 `json:"chance"`
+}
+
+// Roll generates the drops for one kill or chest opening
+func (t LootTable) Roll(rng *rand.Rand) []LootDrop {
+	var drops []LootDrop
+	for _, entry := range t.Guaranteed {
+		drops = append(drops, entry.drop(rng))
+	}
+
+	for i := 0; i < t.Rolls; i++ {
+		if entry, ok := pickWeighted(rng, t.Entries, t.NothingWeight); ok {
+			drops = append(drops, entry.drop(rng))
+		}
+	}
+
+	if len(t.RareTable) > 0 && t.RareTableChance > 0 && rng.Intn(t.RareTableChance) == 0 {
+		if entry, ok := pickWeighted(rng, t.RareTable, 0); ok {
+			drops = append(drops, entry.drop(rng))
+		}
+	}
+
+	return drops
+}
+
+// DropChances returns each weighted entry with its chance of dropping on a
+// single roll, for displaying drop tables
+func (t LootTable) DropChances() []DropChance {
+	return chances(t.Entries, t.NothingWeight, 1)
+}
+
+// RareDropChances returns each rare table entry with its overall chance of
+// dropping, taking the chance of reaching the rare table into account
+func (t LootTable) RareDropChances() []DropChance {
+	if t.RareTableChance <= 0 {
+		return nil
+	}
+	return chances(t.RareTable, 0, 1/float64(t.RareTableChance))
+}
+
+func (e LootEntry) drop(rng *rand.Rand) LootDrop {
+	quantity := e.MinQuantity
+	if e.MaxQuantity > e.MinQuantity {
+		quantity += rng.Intn(e.MaxQuantity - e.MinQuantity + 1)
+	}
+	if quantity < 1 {
+		quantity = 1
+	}
+
+	item := e.Item
+	item.Quantity = quantity
+	return LootDrop{Item: item, Rarity: e.Rarity}
+}
+
+func pickWeighted(rng *rand.Rand, entries []LootEntry, nothingWeight int) (LootEntry, bool) {
+	total := nothingWeight
+	for _, entry := range entries {
+		total += entry.Weight
+	}
+	if total <= 0 {
+		return LootEntry{}, false
+	}
+
+	roll := rng.Intn(total)
+	for _, entry := range entries {
+		if roll < entry.Weight {
+			return entry, true
+		}
+		roll -= entry.Weight
+	}
+	return LootEntry{}, false
+}
+
+func chances(entries []LootEntry, nothingWeight int, scale float64) []DropChance {
+	total := nothingWeight
+	for _, entry := range entries {
+		total += entry.Weight
+	}
+
+	result := make([]DropChance, 0, len(entries))
+	for _, entry := range entries {
+		chance := 0.0
+		if total > 0 {
+			chance = float64(entry.Weight) / float64(total) * scale
+		}
+		result = append(result, DropChance{LootEntry: entry, Chance: chance})
+	}
+	return result
+}
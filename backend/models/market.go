@@ -0,0 +1,190 @@
+package models
+
+import (
+	"sort"
+	"time"
+)
+
+// Order sides
+const (
+	OrderBuy  = "buy"
+	OrderSell = "sell"
+)
+
+// Order statuses
+const (
+	OrderOpen      = "open"
+	OrderFilled    = "filled"
+	OrderCancelled = "cancelled"
+)
+
+// MarketHistorySize is how many recent trades an order book remembers per item
+const MarketHistorySize = 50
+
+// Order is a limit order on the market. Price is per unit; a buy order never
+// pays more than its price and a sell order never receives less.
+type Order struct {
+	ID        uint      `json:"id"`
+	PlayerID  uint      `json:"playerId"`
+	ItemID    uint      `json:"itemId"`
+	Side      string    `json:"side"`
+	Price     int       `json:"price"`
+	Quantity  int       `json:"quantity"`
+	Filled    int       `json:"filled"`
+	Status    string    `json:"status"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Remaining returns how many units of the order are still unfilled
+func (o *Order) Remaining() int {
+	return o.Quantity - o.Filled
+}
+
+// MarketTrade is a fill between a buy order and a sell order
+type MarketTrade struct {
+	ItemID      uint      `json:"itemId"`
+	Price       int       `json:"price"`
+	Quantity    int       `json:"quantity"`
+	BuyOrderID  uint      `json:"buyOrderId"`
+	SellOrderID uint      `json:"sellOrderId"`
+	BuyerID     uint      `json:"buyerId"`
+	SellerID    uint      `json:"sellerId"`
+	ExecutedAt  time.Time `json:"executedAt"`
+}
+
+// PriceLevel is the total quantity resting at one price in an order book
+type PriceLevel struct {
+	Price    int `json:"price"`
+	Quantity int `json:"quantity"`
+	Orders   int `json:"orders"`
+}
+
+// OrderBook holds the resting orders and recent trades for one item. Buys are
+// kept best (highest) price first and sells best (lowest) price first, with
+// older orders ahead of newer ones at the same price. History is newest first.
+type OrderBook struct {
+	ItemID  uint          `json:"itemId"`
+	Buys    []*Order      `json:"-"`
+	Sells   []*Order      `json:"-"`
+	History []MarketTrade `json:"history"`
+}
+
+// Place matches an incoming order against the opposite side of the book by
+// price-time priority, filling at the resting order's price. Any remainder
+// rests in the book. The resulting trades are returned oldest first.
+func (b *OrderBook) Place(order *Order, now time.Time) []MarketTrade {
+	var trades []MarketTrade
+
+	opposite := &b.Sells
+	if order.Side == OrderSell {
+		opposite = &b.Buys
+	}
+
+	for order.Remaining() > 0 && len(*opposite) > 0 {
+		resting := (*opposite)[0]
+		if !crosses(order, resting) {
+			break
+		}
+
+		quantity := order.Remaining()
+		if resting.Remaining() < quantity {
+			quantity = resting.Remaining()
+		}
+		order.Filled += quantity
+		resting.Filled += quantity
+
+		trade := MarketTrade{
+			ItemID:     b.ItemID,
+			Price:      resting.Price,
+			Quantity:   quantity,
+			ExecutedAt: now,
+		}
+		if order.Side == OrderBuy {
+			trade.BuyOrderID, trade.BuyerID = order.ID, order.PlayerID
+			trade.SellOrderID, trade.SellerID = resting.ID, resting.PlayerID
+		} else {
+			trade.BuyOrderID, trade.BuyerID = resting.ID, resting.PlayerID
+			trade.SellOrderID, trade.SellerID = order.ID, order.PlayerID
+		}
+		trades = append(trades, trade)
+		b.record(trade)
+
+		if resting.Remaining() == 0 {
+			resting.Status = OrderFilled
+			*opposite = (*opposite)[1:]
+		}
+	}
+
+	if order.Remaining() == 0 {
+		order.Status = OrderFilled
+		return trades
+	}
+
+	order.Status = OrderOpen
+	b.rest(order)
+	return trades
+}
+
+// Cancel removes an open order from the book
+func (b *OrderBook) Cancel(order *Order) {
+	side := &b.Buys
+	if order.Side == OrderSell {
+		side = &b.Sells
+	}
+	for i, resting := range *side {
+		if resting.ID == order.ID {
+			*side = append((*side)[:i], (*side)[i+1:]...)
+			break
+		}
+	}
+	order.Status = OrderCancelled
+}
+
+// Depth returns the book aggregated into price levels, best prices first
+func (b *OrderBook) Depth() (buys, sells []PriceLevel) {
+	return aggregate(b.Buys), aggregate(b.Sells)
+}
+
+func (b *OrderBook) rest(order *Order) {
+	side := &b.Buys
+	better := func(x, y *Order) bool { return x.Price > y.Price }
+	if order.Side == OrderSell {
+		side = &b.Sells
+		better = func(x, y *Order) bool { return x.Price < y.Price }
+	}
+
+	// Insert after every order with a better or equal price to keep time priority
+	index := sort.Search(len(*side), func(i int) bool {
+		return better(order, (*side)[i])
+	})
+	*side = append(*side, nil)
+	copy((*side)[index+1:], (*side)[index:])
+	(*side)[index] = order
+}
+
+func (b *OrderBook) record(trade MarketTrade) {
+	b.History = append([]MarketTrade{trade}, b.History...)
+	if len(b.History) > MarketHistorySize {
+		b.History = b.History[:MarketHistorySize]
+	}
+}
+
+func crosses(incoming, resting *Order) bool {
+	if incoming.Side == OrderBuy {
+		return incoming.Price >= resting.Price
+	}
+	return incoming.Price <= resting.Price
+}
+
+func aggregate(orders []*Order) []PriceLevel {
+	levels := []PriceLevel{}
+	for _, order := range orders {
+		if len(levels) > 0 && levels[len(levels)-1].Price == order.Price {
+			levels[len(levels)-1].Quantity += order.Remaining()
+			levels[len(levels)-1].Orders++
+			continue
+		}
+		levels = append(levels, PriceLevel{Price: order.Price, Quantity: order.Remaining(), Orders: 1})
+	}
+	return levels
+}
@@ -9,6 +9,10 @@ type Enemy struct {
     Defense        int             `json:"defense"`
     AttackSpeed    int             `json:"attackSpeed"`
     SpecialAbility *SpecialAbility `json:"specialAbility,omitempty"`
+    // New fields for the combat triangle
+    Style          string          `json:"style,omitempty"`      // melee, ranged or magic
+    Defenses       map[string]int  `json:"defenses,omitempty"`   // defense against a damage type, replacing Defense
+    Weaknesses     []string        `json:"weaknesses,omitempty"` // damage types that deal extra damage
 }
 
 type SpecialAbility struct {
@@ -25,6 +25,7 @@ type CombatAbility struct {
 type StatusEffect struct {
 	Type     string    `json:"type"`
 	Damage   int       `json:"damage,omitempty"`
+	Healing  int       `json:"healing,omitempty"`
 	Duration int       `json:"duration"`
 	EndTime  time.Time `json:"endTime"`
 }
@@ -52,16 +53,28 @@ type Player struct {
 	CreatedAt         time.Time       `json:"created_at"`
 	UpdatedAt         time.Time       `json:"updated_at"`
 	// New fields for skill progression
-	SkillPoints int `json:"skillPoints"`
-	SkillCap    int `json:"skillCap"`
+	SkillPoints     int                   `json:"skillPoints"`
+	SkillCap        int                   `json:"skillCap"`
+	SkillExperience PlayerSkillExperience `json:"skillExperience" gorm:"-"`
 	// New fields for equipment
 	EquippedWeapon *InventoryItem `json:"equippedWeapon" gorm:"-"`
 	EquippedArmor  *InventoryItem `json:"equippedArmor" gorm:"揽"`
+	EquippedCape   *InventoryItem `json:"equippedCape" gorm:"-"`
+	Cape           string         `json:"cape"`
 	// New fields for achievements
 	Achievements []Achievement `json:"achievements" gorm:"foreignKey:PlayerID"`
+	Titles       []string      `json:"titles" gorm:"-"`
+	// New fields for the world map
+	Location string `json:"location"`
+	Home     string `json:"home"`
+	// New fields for recovery
+	Regeneration Regeneration `json:"regeneration" gorm:"-"`
+	// New fields for combat styles
+	CombatStyle string `json:"combatStyle" gorm:"-"`
 }
 
-// CalculateAttackDamage returns the player's attack damage based on equipped weapon, combat skill, and relevant stat
+// CalculateAttackDamage returns the player's attack damage based on equipped weapon and cape, combat skill, and relevant stat.
+// A broken weapon adds nothing until it is repaired.
 func (p *Player) CalculateAttackDamage(damageType string) int {
 	baseDamage := 5 + p.Skills.Combat
 
@@ -75,18 +88,32 @@ func (p *Player) CalculateAttackDamage(damageType string) int {
 		baseDamage += p.Magic * 2
 	}
 
-	if p.EquippedWeapon != nil {
-		return baseDamage + p.EquippedWeapon.Stats.Attack
+	if p.EquippedCape != nil {
+		baseDamage += p.EquippedCape.Stats.Attack
+		if damageType == "magic" {
+			baseDamage += p.EquippedCape.Stats.MagicPower
+		}
+	}
+
+	if p.EquippedWeapon != nil && !p.EquippedWeapon.Broken() {
+		baseDamage += p.EquippedWeapon.Stats.Attack
+		if damageType == "magic" {
+			baseDamage += p.EquippedWeapon.Stats.MagicPower
+		}
 	}
 	return baseDamage
 }
 
-// CalculateDefense returns the player's defense based on equipped armor and stats
+// CalculateDefense returns the player's defense based on equipped armor and cape and stats
 func (p *Player) CalculateDefense() int {
 	baseDefense := 2 + (p.Skills.Combat / 2)
 	// Add stat-based defense
 	baseDefense += (p.Strength / 2) + (p.Dexterity / 3)
 
+	if p.EquippedCape != nil {
+		baseDefense += p.EquippedCape.Stats.Defense
+	}
+
 	if p.EquippedArmor != nil {
 		return baseDefense + p.EquippedArmor.Stats.Defense
 	}
@@ -153,9 +180,8 @@ func (p *Player) ApplyStatusEffect(effect StatusEffect) {
 	p.StatusEffects = append(p.StatusEffects, effect)
 }
 
-// UpdateStatusEffects removes expired status effects and applies damage from active ones
-func (p *Player) UpdateStatusEffects() {
-	now := time.Now()
+// UpdateStatusEffects removes expired status effects and applies damage and healing from active ones
+func (p *Player) UpdateStatusEffects(now time.Time) {
 	activeEffects := make([]StatusEffect, 0)
 
 	for _, effect := range p.StatusEffects {
@@ -164,6 +190,10 @@ func (p *Player) UpdateStatusEffects() {
 			if effect.Damage > 0 {
 				p.TakeDamage(effect.Damage)
 			}
+			// Heal over time under the same rules as any other recovery
+			if effect.Healing > 0 {
+				p.Recover(effect.Healing, 0)
+			}
 			activeEffects = append(activeEffects, effect)
 		}
 	}
@@ -183,6 +213,12 @@ func (p *Player) TakeDamage(damage int) {
 	}
 }
 
+// Heal restores health up to the player's maximum and returns how much was restored
+func (p *Player) Heal(amount int) int {
+	healed, _ := p.Recover(amount, 0)
+	return healed
+}
+
 // CalculateExperienceGain returns the experience points gained from defeating an enemy
 func (p *Player) CalculateExperienceGain(enemyLevel int) int {
 	levelDifference := enemyLevel - p.Level
@@ -242,6 +278,60 @@ func (p *Player) AddItemToInventory(item InventoryItem) {
 	p.Inventory.Materials = append(p.Inventory.Materials, item)
 }
 
+// MaxInventorySlots is the number of distinct item stacks a player can carry
+const MaxInventorySlots = 28
+
+// CanAddItem reports whether the item fits in the player's inventory, either
+// by stacking onto an existing item or by taking a free slot
+func (p *Player) CanAddItem(itemID uint) bool {
+	for _, invItem := range p.Inventory.Materials {
+		if invItem.ID == itemID {
+			return true
+		}
+	}
+	return len(p.Inventory.Materials) < MaxInventorySlots
+}
+
+// CanAddItems reports whether all of the items fit in the player's inventory at once
+func (p *Player) CanAddItems(items []InventoryItem) bool {
+	newStacks := map[uint]bool{}
+	for _, item := range items {
+		if p.ItemQuantity(item.ID) == 0 {
+			newStacks[item.ID] = true
+		}
+	}
+	return len(p.Inventory.Materials)+len(newStacks) <= MaxInventorySlots
+}
+
+// ItemQuantity returns how many of the given item the player is carrying
+func (p *Player) ItemQuantity(itemID uint) int {
+	for _, item := range p.Inventory.Materials {
+		if item.ID == itemID {
+			return item.Quantity
+		}
+	}
+	return 0
+}
+
+// RemoveItemFromInventory removes a quantity of an item, returning false and
+// leaving the inventory untouched if the player does not have enough
+func (p *Player) RemoveItemFromInventory(itemID uint, quantity int) bool {
+	for i, item := range p.Inventory.Materials {
+		if item.ID == itemID {
+			if item.Quantity < quantity {
+				return false
+			}
+			p.Inventory.Materials[i].Quantity -= quantity
+			if p.Inventory.Materials[i].Quantity <= 0 {
+				// Remove item if quantity reaches 0
+				p.Inventory.Materials = append(p.Inventory.Materials[:i], p.Inventory.Materials[i+1:]...)
+			}
+			return true
+		}
+	}
+	return false
+}
+
 // HasIngredients checks if the player has the required ingredients for alchemy
 func (p *Player) HasIngredients(ingredients []FormulaIngredient) bool {
 	for _, ingredient := range ingredients {
@@ -282,6 +372,9 @@ type PlayerSkills struct {
 	Farming  int `json:"farming"`
 	Crafting int `json:"crafting"`
 	Alchemy  int `json:"alchemy"`
+	// Gathering skills
+	Mining      int `json:"mining" gorm:"-"`
+	Woodcutting int `json:"woodcutting" gorm:"-"`
 }
 
 type PlayerInventory struct {
@@ -305,22 +398,36 @@ type InventoryItem struct {
 	ID          uint      `json:"id"`
 	Name        string    `json:"name"`
 	Description string    `json:"description"`
+	Type        string    `json:"type,omitempty"`  // weapon, armor, consumable, material
+	Style       string    `json:"style,omitempty"` // melee, ranged or magic for weapons and ammo
 	Quantity    int       `json:"quantity"`
 	Stats       ItemStats `json:"stats"`
 }
 
+// Broken reports whether the item has worn out and needs repairing
+func (i InventoryItem) Broken() bool {
+	return i.Stats.MaxDurability > 0 && i.Stats.Durability <= 0
+}
+
 type ItemStats struct {
 	Attack     int `json:"attack"`
 	Defense    int `json:"defense"`
 	MagicPower int `json:"magicPower"`
 	Durability int `json:"durability"`
+	Healing    int `json:"healing,omitempty"`
+	// HealingOverTime is health restored every second for HealingDuration seconds
+	HealingOverTime int `json:"healingOverTime,omitempty"`
+	HealingDuration int `json:"healingDuration,omitempty"`
+	// MaxDurability is the durability a repair restores. Items without one never wear out.
+	MaxDurability int `json:"maxDurability,omitempty"`
 }
 
 type Achievement struct {
-	ID          uint      `json:"id" gorm:"primaryKey"`
-	PlayerID    uint      `json:"player_id"`
-	Name        string    `json:"name"`
-	Description string    `json:"description"`
-	CompletedAt time.Time `json:"completed_at"`
-	Reward      string    `json:"reward"`
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	PlayerID      uint      `json:"player_id"`
+	AchievementID uint      `json:"achievement_id" gorm:"-"`
+	Name          string    `json:"name"`
+	Description   string    `json:"description"`
+	CompletedAt   time.Time `json:"completed_at"`
+	Reward        string    `json:"reward"`
 }
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"fmt"
+	"math"
+)
+
+// ProgressionConfig controls how character levels are earned and what each
+// level grants. The experience needed to go from level n to n+1 is
+// BaseExperience * Growth^(n-1).
+type ProgressionConfig struct {
+	BaseExperience      int     `json:"baseExperience"`
+	Growth              float64 `json:"growth"`
+	MaxLevel            int     `json:"maxLevel"`
+	HealthPerLevel      int     `json:"healthPerLevel"`
+	StaminaPerLevel     int     `json:"staminaPerLevel"`
+	SkillPointsPerLevel int     `json:"skillPointsPerLevel"`
+	BaseStamina         int     `json:"baseStamina"`
+	StaminaPerDexterity int     `json:"staminaPerDexterity"`
+}
+
+// Limits on configured curves. Experience thresholds are capped so steep
+// curves and high levels can't overflow.
+const (
+	maxGrowth            = 10.0
+	maxLevelCap          = 1000
+	maxExperienceToLevel = math.MaxInt32
+)
+
+// DefaultProgression matches the original curve of 100 experience for level 2
+// growing by half again for every level after
+var DefaultProgression = ProgressionConfig{
+	BaseExperience:      100,
+	Growth:              1.5,
+	MaxLevel:            99,
+	HealthPerLevel:      20,
+	StaminaPerLevel:     5,
+	SkillPointsPerLevel: 3,
+	BaseStamina:         100,
+	StaminaPerDexterity: 2,
+}
+
+// Validate reports whether the config describes a usable curve
+func (c ProgressionConfig) Validate() error {
+	if c.BaseExperience < 1 {
+		return fmt.Errorf("baseExperience must be at least 1")
+	}
+	if c.Growth < 1 || c.Growth > maxGrowth {
+		return fmt.Errorf("growth must be between 1 and %g", maxGrowth)
+	}
+	if c.MaxLevel < 1 || c.MaxLevel > maxLevelCap {
+		return fmt.Errorf("maxLevel must be between 1 and %d", maxLevelCap)
+	}
+	if c.HealthPerLevel < 0 || c.StaminaPerLevel < 0 || c.SkillPointsPerLevel < 0 {
+		return fmt.Errorf("per level rewards cannot be negative")
+	}
+	if c.BaseStamina < 1 || c.StaminaPerDexterity < 0 {
+		return fmt.Errorf("baseStamina must be at least 1 and staminaPerDexterity cannot be negative")
+	}
+	return nil
+}
+
+// ExperienceToLevel returns the experience needed to advance past the given
+// level, capped at maxExperienceToLevel
+func (c ProgressionConfig) ExperienceToLevel(level int) int {
+	if level < 1 {
+		level = 1
+	}
+	experience := float64(c.BaseExperience) * math.Pow(c.Growth, float64(level-1))
+	if experience > maxExperienceToLevel || math.IsNaN(experience) {
+		return maxExperienceToLevel
+	}
+	return int(experience)
+}
+
+// LevelUp describes the rewards from one call to GainExperience
+type LevelUp struct {
+	Levels      int `json:"levels"`
+	Level       int `json:"level"`
+	MaxHealth   int `json:"maxHealth"`
+	MaxStamina  int `json:"maxStamina"`
+	SkillPoints int `json:"skillPoints"`
+}
+
+// GainExperience adds character experience, advancing as many levels as it
+// pays for and carrying the remainder over. Every level gained raises max
+// health and stamina, grants skill points, and fully restores the player.
+func (p *Player) GainExperience(amount int, config ProgressionConfig) LevelUp {
+	result := LevelUp{Level: p.Level}
+	if amount <= 0 {
+		return result
+	}
+
+	p.Experience += amount
+	p.ExperienceToLevel = config.ExperienceToLevel(p.Level)
+
+	for p.Level < config.MaxLevel && p.Experience >= p.ExperienceToLevel {
+		p.Experience -= p.ExperienceToLevel
+		p.Level++
+		p.MaxHealth += config.HealthPerLevel
+		p.MaxStamina += config.StaminaPerLevel
+		p.SkillPoints += config.SkillPointsPerLevel
+		p.ExperienceToLevel = config.ExperienceToLevel(p.Level)
+
+		result.Levels++
+		result.MaxHealth += config.HealthPerLevel
+		result.MaxStamina += config.StaminaPerLevel
+		result.SkillPoints += config.SkillPointsPerLevel
+	}
+
+	if result.Levels > 0 {
+		p.Recover(p.MaxHealth, p.MaxStamina)
+	}
+	result.Level = p.Level
+	return result
+}
@@ -0,0 +1,85 @@
+package models
+
+import "time"
+
+// RegenerationInterval is how often natural regeneration restores health and stamina
+const RegenerationInterval = 5 * time.Second
+
+// Regeneration is how quickly a player is recovering health and stamina
+type Regeneration struct {
+	// Health and Stamina are restored every RegenerationInterval
+	Health  int `json:"health"`
+	Stamina int `json:"stamina"`
+	// HealthPerMinute and StaminaPerMinute include healing over time effects
+	HealthPerMinute  int  `json:"healthPerMinute"`
+	StaminaPerMinute int  `json:"staminaPerMinute"`
+	InCombat         bool `json:"inCombat"`
+}
+
+// RegenerationRate returns the player's current recovery rates. Health only
+// regenerates naturally out of combat, and stamina regenerates faster out of
+// combat, but healing over time effects always apply.
+func (p *Player) RegenerationRate(inCombat bool) Regeneration {
+	rate := Regeneration{Stamina: 1, InCombat: inCombat}
+	if !inCombat {
+		rate.Health = 1 + p.MaxHealth/100
+		rate.Stamina = 2 + p.Dexterity/10
+	}
+
+	ticksPerMinute := int(time.Minute / RegenerationInterval)
+	rate.HealthPerMinute = rate.Health * ticksPerMinute
+	rate.StaminaPerMinute = rate.Stamina * ticksPerMinute
+	for _, effect := range p.StatusEffects {
+		rate.HealthPerMinute += effect.Healing * 60
+	}
+	return rate
+}
+
+// Recover restores health and stamina up to the player's maximums and
+// returns how much of each was restored. Dead players don't recover.
+func (p *Player) Recover(health, stamina int) (int, int) {
+	if p.Health <= 0 {
+		return 0, 0
+	}
+	beforeHealth, beforeStamina := p.Health, p.Stamina
+	if health > 0 {
+		p.Health = min(p.MaxHealth, p.Health+health)
+	}
+	if stamina > 0 {
+		p.Stamina = min(p.MaxStamina, p.Stamina+stamina)
+	}
+	return p.Health - beforeHealth, p.Stamina - beforeStamina
+}
+
+// EffectRegeneration is the status effect that heals over time
+const EffectRegeneration = "regeneration"
+
+// ApplyHealingOverTime restores health every second for the given number of
+// seconds, replacing any healing over time already in effect
+func (p *Player) ApplyHealingOverTime(perSecond, seconds int, now time.Time) {
+	effects := make([]StatusEffect, 0, len(p.StatusEffects)+1)
+	for _, effect := range p.StatusEffects {
+		if effect.Type != EffectRegeneration {
+			effects = append(effects, effect)
+		}
+	}
+	p.StatusEffects = append(effects, StatusEffect{
+		Type:     EffectRegeneration,
+		Healing:  perSecond,
+		Duration: seconds,
+		EndTime:  now.Add(time.Duration(seconds) * time.Second),
+	})
+}
+
+// IsFullyRested reports whether the player is at full health and stamina
+func (p *Player) IsFullyRested() bool {
+	return p.Health >= p.MaxHealth && p.Stamina >= p.MaxStamina
+}
+
+// Inn is where a player can pay to rest and fully recover
+type Inn struct {
+	ID       uint   `json:"id"`
+	Name     string `json:"name"`
+	Location string `json:"location"`
+	Cost     int    `json:"cost"`
+}
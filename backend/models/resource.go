@@ -0,0 +1,66 @@
+package models
+
+import (
+	"time"
+)
+
+// ResourceNode is a rock or tree in the world that players mine or chop.
+// Each successful gather uses up a charge, and once they run out the node
+// is depleted for everyone until RespawnTime has passed.
+type ResourceNode struct {
+	ID           uint          `json:"id"`
+	Name         string        `json:"name"`
+	Skill        string        `json:"skill"`
+	Location     string        `json:"location"`
+	Level        int           `json:"level"`
+	Experience   int           `json:"experience"`
+	ItemID       uint          `json:"itemId"`
+	BaseDuration time.Duration `json:"baseDuration"`
+	Charges      int           `json:"charges"`
+	MaxCharges   int           `json:"maxCharges"`
+	RespawnTime  time.Duration `json:"respawnTime"`
+	DepletedAt   time.Time     `json:"depletedAt"`
+}
+
+// Update respawns a depleted node once its respawn time has passed
+func (n *ResourceNode) Update(now time.Time) {
+	if n.IsDepleted() && !now.Before(n.DepletedAt.Add(n.RespawnTime)) {
+		n.Charges = n.MaxCharges
+		n.DepletedAt = time.Time{}
+	}
+}
+
+// IsDepleted reports whether the node has no charges left
+func (n *ResourceNode) IsDepleted() bool {
+	return n.Charges <= 0
+}
+
+// Gather uses up one charge, returning false if the node was already depleted
+func (n *ResourceNode) Gather(now time.Time) bool {
+	n.Update(now)
+	if n.IsDepleted() {
+		return false
+	}
+	n.Charges--
+	if n.Charges == 0 {
+		n.DepletedAt = now
+	}
+	return true
+}
+
+// GatherDuration returns how long one gather takes with a tool of the given speed
+func (n *ResourceNode) GatherDuration(tool GatheringTool) time.Duration {
+	if tool.Speed <= 0 {
+		return n.BaseDuration
+	}
+	return time.Duration(float64(n.BaseDuration) / tool.Speed)
+}
+
+// GatheringTool is a pickaxe or axe. Better tools need a higher level in
+// their skill and gather faster.
+type GatheringTool struct {
+	ItemID uint    `json:"itemId"`
+	Skill  string  `json:"skill"`
+	Level  int     `json:"level"`
+	Speed  float64 `json:"speed"`
+}
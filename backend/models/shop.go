@@ -0,0 +1,62 @@
+package models
+
+import (
+	"time"
+)
+
+// ShopItem is an item a shop sells. BuyPrice is what the player pays and
+// SellPrice is what the shop pays the player for one.
+type ShopItem struct {
+	Item            InventoryItem `json:"item"`
+	BuyPrice        int           `json:"buyPrice"`
+	SellPrice       int           `json:"sellPrice"`
+	Stock           int           `json:"stock"`
+	MaxStock        int           `json:"maxStock"`
+	RestockInterval time.Duration `json:"restockInterval"` // time to restock one unit
+	LastRestock     time.Time     `json:"lastRestock"`
+}
+
+type Shop struct {
+	ID       uint       `json:"id"`
+	Name     string     `json:"name"`
+	NPC      string     `json:"npc"`
+	Location string     `json:"location"`
+	Items    []ShopItem `json:"items"`
+	// Repairs is whether the shopkeeper repairs worn weapons
+	Repairs bool `json:"repairs,omitempty"`
+}
+
+// FindItem returns the shop's listing for an item, or nil if it doesn't trade it
+func (s *Shop) FindItem(itemID uint) *ShopItem {
+	for i := range s.Items {
+		if s.Items[i].Item.ID == itemID {
+			return &s.Items[i]
+		}
+	}
+	return nil
+}
+
+// Restock tops up every item by one unit per elapsed restock interval, up to its maximum stock
+func (s *Shop) Restock(now time.Time) {
+	for i := range s.Items {
+		s.Items[i].restock(now)
+	}
+}
+
+func (i *ShopItem) restock(now time.Time) {
+	if i.LastRestock.IsZero() || i.Stock >= i.MaxStock || i.RestockInterval <= 0 {
+		i.LastRestock = now
+		return
+	}
+
+	units := int(now.Sub(i.LastRestock) / i.RestockInterval)
+	if units <= 0 {
+		return
+	}
+
+	i.Stock += units
+	if i.Stock > i.MaxStock {
+		i.Stock = i.MaxStock
+	}
+	i.LastRestock = i.LastRestock.Add(time.Duration(units) * i.RestockInterval)
+}
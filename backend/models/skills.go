@@ -0,0 +1,168 @@
+package models
+
+import (
+	"math"
+)
+
+// Skill names as used in requests and responses
+const (
+	SkillCombat   = "combat"
+	SkillFishing  = "fishing"
+	SkillCooking  = "cooking"
+	SkillFarming  = "farming"
+	SkillCrafting = "crafting"
+	SkillAlchemy  = "alchemy"
+	// Gathering skills
+	SkillMining      = "mining"
+	SkillWoodcutting = "woodcutting"
+)
+
+// SkillNames lists every skill in display order
+var SkillNames = []string{SkillCombat, SkillFishing, SkillCooking, SkillFarming, SkillCrafting, SkillAlchemy, SkillMining, SkillWoodcutting}
+
+// ExperienceCurve maps total experience to a level. Thresholds[i] is the
+// experience needed to reach level i+1, so Thresholds[0] is always zero.
+type ExperienceCurve struct {
+	Thresholds []int `json:"thresholds"`
+}
+
+// NewExponentialCurve builds a curve where the experience needed per level
+// doubles every doublingLevels levels. NewExponentialCurve(99, 300, 7) is the
+// classic RuneScape table, reaching 13,034,431 experience at level 99.
+func NewExponentialCurve(maxLevel int, scale, doublingLevels float64) ExperienceCurve {
+	thresholds := []int{0}
+	points := 0.0
+	for level := 1; level < maxLevel; level++ {
+		points += math.Floor(float64(level) + scale*math.Pow(2, float64(level)/doublingLevels))
+		thresholds = append(thresholds, int(math.Floor(points/4)))
+	}
+	return ExperienceCurve{Thresholds: thresholds}
+}
+
+// SkillCurve is the experience table every skill levels along
+var SkillCurve = NewExponentialCurve(99, 300, 7)
+
+// MaxLevel returns the highest level on the curve
+func (c ExperienceCurve) MaxLevel() int {
+	return len(c.Thresholds)
+}
+
+// LevelForExperience returns the level reached with the given total experience
+func (c ExperienceCurve) LevelForExperience(experience int) int {
+	level := 1
+	for i, threshold := range c.Thresholds {
+		if experience >= threshold {
+			level = i + 1
+		}
+	}
+	return level
+}
+
+// ExperienceForLevel returns the total experience needed to reach a level
+func (c ExperienceCurve) ExperienceForLevel(level int) int {
+	if level <= 1 {
+		return 0
+	}
+	if level > len(c.Thresholds) {
+		level = len(c.Thresholds)
+	}
+	return c.Thresholds[level-1]
+}
+
+// PlayerSkillExperience holds the total experience earned in each skill.
+// Skill levels in PlayerSkills are derived from these totals.
+type PlayerSkillExperience struct {
+	Combat   int `json:"combat"`
+	Fishing  int `json:"fishing"`
+	Cooking  int `json:"cooking"`
+	Farming  int `json:"farming"`
+	Crafting int `json:"crafting"`
+	Alchemy  int `json:"alchemy"`
+	// Gathering skills
+	Mining      int `json:"mining"`
+	Woodcutting int `json:"woodcutting"`
+}
+
+// skill returns pointers to the level and experience of the named skill
+func (p *Player) skill(name string) (level, experience *int) {
+	switch name {
+	case SkillCombat:
+		return &p.Skills.Combat, &p.SkillExperience.Combat
+	case SkillFishing:
+		return &p.Skills.Fishing, &p.SkillExperience.Fishing
+	case SkillCooking:
+		return &p.Skills.Cooking, &p.SkillExperience.Cooking
+	case SkillFarming:
+		return &p.Skills.Farming, &p.SkillExperience.Farming
+	case SkillCrafting:
+		return &p.Skills.Crafting, &p.SkillExperience.Crafting
+	case SkillAlchemy:
+		return &p.Skills.Alchemy, &p.SkillExperience.Alchemy
+	case SkillMining:
+		return &p.Skills.Mining, &p.SkillExperience.Mining
+	case SkillWoodcutting:
+		return &p.Skills.Woodcutting, &p.SkillExperience.Woodcutting
+	}
+	return nil, nil
+}
+
+// SkillLevel returns the player's level in the named skill, or zero if there is no such skill
+func (p *Player) SkillLevel(name string) int {
+	level, _ := p.skill(name)
+	if level == nil {
+		return 0
+	}
+	return *level
+}
+
+// AddSkillExperience grants experience in a skill and recalculates its level,
+// which never exceeds the player's skill cap. It returns the number of levels gained.
+func (p *Player) AddSkillExperience(name string, amount int) int {
+	level, experience := p.skill(name)
+	if level == nil || amount <= 0 {
+		return 0
+	}
+
+	*experience += amount
+	newLevel := SkillCurve.LevelForExperience(*experience)
+	if p.SkillCap > 0 && newLevel > p.SkillCap {
+		newLevel = p.SkillCap
+	}
+
+	gained := newLevel - *level
+	if gained < 0 {
+		gained = 0
+	}
+	*level = newLevel
+	return gained
+}
+
+// SkillProgress describes a skill's level and the experience to its next level
+type SkillProgress struct {
+	Skill            string `json:"skill"`
+	Level            int    `json:"level"`
+	Experience       int    `json:"experience"`
+	NextLevelAt      int    `json:"nextLevelAt"`
+	ExperienceToNext int    `json:"experienceToNext"`
+}
+
+// SkillProgress returns the progress of every skill, in a fixed order
+func (p *Player) SkillProgress() []SkillProgress {
+	progress := make([]SkillProgress, 0, len(SkillNames))
+	for _, name := range SkillNames {
+		level, experience := p.skill(name)
+		next := SkillCurve.ExperienceForLevel(*level + 1)
+		toNext := next - *experience
+		if toNext < 0 || *level >= SkillCurve.MaxLevel() || (p.SkillCap > 0 && *level >= p.SkillCap) {
+			toNext = 0
+		}
+		progress = append(progress, SkillProgress{
+			Skill:            name,
+			Level:            *level,
+			Experience:       *experience,
+			NextLevelAt:      next,
+			ExperienceToNext: toNext,
+		})
+	}
+	return progress
+}
@@ -0,0 +1,92 @@
+package models
+
+import (
+	"time"
+)
+
+// Trade statuses
+const (
+	TradeOpen      = "open"
+	TradeCompleted = "completed"
+	TradeCancelled = "cancelled"
+	TradeExpired   = "expired"
+)
+
+// TradeTimeout is how long a trade may sit without changes before it expires
+const TradeTimeout = 5 * time.Minute
+
+// TradeOffer is one side of a trade. Offered items and gold are held in
+// escrow, already removed from the player, until the trade ends.
+type TradeOffer struct {
+	PlayerID  uint            `json:"playerId"`
+	Items     []InventoryItem `json:"items"`
+	Gold      int             `json:"gold"`
+	Confirmed bool            `json:"confirmed"`
+}
+
+type Trade struct {
+	ID        uint       `json:"id"`
+	Initiator TradeOffer `json:"initiator"`
+	Recipient TradeOffer `json:"recipient"`
+	Status    string     `json:"status"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+// NewTrade opens a trade proposed by one player to another
+func NewTrade(id, initiatorID, recipientID uint, now time.Time) *Trade {
+	return &Trade{
+		ID:        id,
+		Initiator: TradeOffer{PlayerID: initiatorID},
+		Recipient: TradeOffer{PlayerID: recipientID},
+		Status:    TradeOpen,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Offers returns the given player's offer and their partner's, or nil if the
+// player is not part of the trade
+func (t *Trade) Offers(playerID uint) (own, partner *TradeOffer) {
+	switch playerID {
+	case t.Initiator.PlayerID:
+		return &t.Initiator, &t.Recipient
+	case t.Recipient.PlayerID:
+		return &t.Recipient, &t.Initiator
+	}
+	return nil, nil
+}
+
+// Involves reports whether the player is one of the two trading parties
+func (t *Trade) Involves(playerID uint) bool {
+	return t.Initiator.PlayerID == playerID || t.Recipient.PlayerID == playerID
+}
+
+// Touch records a change to the trade. Any change to either offer means both
+// players have to confirm the new state again.
+func (t *Trade) Touch(now time.Time) {
+	t.Initiator.Confirmed = false
+	t.Recipient.Confirmed = false
+	t.UpdatedAt = now
+}
+
+// IsExpired reports whether an open trade has gone untouched for too long
+func (t *Trade) IsExpired(now time.Time) bool {
+	return t.Status == TradeOpen && now.Sub(t.UpdatedAt) > TradeTimeout
+}
+
+// BothConfirmed reports whether both players have agreed to the current offers
+func (t *Trade) BothConfirmed() bool {
+	return t.Initiator.Confirmed && t.Recipient.Confirmed
+}
+
+// AddItem places an item in escrow, stacking it with any of the same item already offered
+func (o *TradeOffer) AddItem(item InventoryItem) {
+	for i := range o.Items {
+		if o.Items[i].ID == item.ID {
+			o.Items[i].Quantity += item.Quantity
+			return
+		}
+	}
+	o.Items = append(o.Items, item)
+}
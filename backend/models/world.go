@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Location is a place in the world. Content such as shops, stations and
+// fishing spots refers to a location by its name.
+type Location struct {
+	ID          uint   `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// Route connects two locations in both directions
+type Route struct {
+	From     uint          `json:"from"`
+	To       uint          `json:"to"`
+	Duration time.Duration `json:"duration"`
+}
+
+// Reversed returns the same route travelled the other way
+func (r Route) Reversed() Route {
+	return Route{From: r.To, To: r.From, Duration: r.Duration}
+}
+
+// TravelAction is a player on their way from one location to another
+type TravelAction struct {
+	PlayerID  uint          `json:"playerId"`
+	From      string        `json:"from"`
+	To        string        `json:"to"`
+	StartedAt time.Time     `json:"startedAt"`
+	Duration  time.Duration `json:"duration"`
+}
+
+// ArrivesAt returns when the player reaches their destination
+func (a TravelAction) ArrivesAt() time.Time {
+	return a.StartedAt.Add(a.Duration)
+}
+
+// HasArrived reports whether the player has reached their destination by now
+func (a TravelAction) HasArrived(now time.Time) bool {
+	return !now.Before(a.ArrivesAt())
+}
+
+// Remaining returns how long is left until the player arrives
+func (a TravelAction) Remaining(now time.Time) time.Duration {
+	if a.HasArrived(now) {
+		return 0
+	}
+	return a.ArrivesAt().Sub(now)
+}
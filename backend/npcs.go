@@ -0,0 +1,468 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"galycherrygame/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// npcs holds every character the player can talk to, keyed by NPC ID.
+// Quest IDs are positions in availableQuests, counting from 1.
+var npcs = map[uint]*models.NPC{
+	1: {
+		ID:          1,
+		Name:        "Elder Rowan",
+		Description: "The village elder, who remembers when the woods were safe",
+		Location:    "Cherry Village",
+		Start:       "greeting",
+		Nodes: map[string]models.DialogueNode{
+			"greeting": {
+				ID:   "greeting",
+				Text: "Welcome, traveller. These are troubled times for Cherry Village.",
+				Choices: []models.DialogueChoice{
+					{Text: "What troubles you?", Next: "troubles",
+						Conditions: []models.DialogueCondition{{Type: models.ConditionQuestState, QuestID: 1, State: models.QuestNone}}},
+					{Text: "About those goblins...", Next: "progress",
+						Conditions: []models.DialogueCondition{{Type: models.ConditionQuestState, QuestID: 1, State: models.QuestActive}}},
+					{Text: "Tell me about the world beyond the village.", Next: "lore"},
+					{Text: "Farewell."},
+				},
+			},
+			"troubles": {
+				ID:   "troubles",
+				Text: "Goblins from the Whispering Woods raid our orchards every night. Will you drive them back?",
+				Choices: []models.DialogueChoice{
+					{Text: "I'll deal with them.", Next: "accepted",
+						Actions: []models.DialogueAction{
+							{Type: models.ActionStartQuest, QuestID: 1},
+							{Type: models.ActionGiveItem, ItemID: 3, Amount: 3},
+						}},
+					{Text: "Not right now.", Next: "greeting"},
+				},
+			},
+			"accepted": {
+				ID:   "accepted",
+				Text: "Bless you. Take these potions, and bring back their ears as proof.",
+				Choices: []models.DialogueChoice{
+					{Text: "Farewell."},
+				},
+			},
+			"progress": {
+				ID:         "progress",
+				Text:       "You've brought proof! Five ears and the village will sleep soundly again.",
+				Conditions: []models.DialogueCondition{{Type: models.ConditionHasItem, ItemID: 5, Amount: 1}},
+				Else:       "progress_none",
+				Choices: []models.DialogueChoice{
+					{Text: "Here are five goblin ears.", Next: "completed",
+						Conditions: []models.DialogueCondition{{Type: models.ConditionHasItem, ItemID: 5, Amount: 5}},
+						Actions: []models.DialogueAction{
+							{Type: models.ActionCompleteQuest, QuestID: 1, ItemID: 5, Amount: 5},
+							{Type: models.ActionGiveItem, ItemID: 8, Amount: 1},
+						}},
+					{Text: "I'll bring more."},
+				},
+			},
+			"completed": {
+				ID:   "completed",
+				Text: "The raids have stopped at last. Take this blade, it served me well in my day.",
+				Choices: []models.DialogueChoice{
+					{Text: "Thank you, elder."},
+				},
+			},
+			"progress_none": {
+				ID:   "progress_none",
+				Text: "The goblins still gather in the Whispering Woods. Bring me their ears.",
+				Choices: []models.DialogueChoice{
+					{Text: "I'm on it."},
+				},
+			},
+			"lore": {
+				ID:   "lore",
+				Text: "East lie the Whispering Woods, and past them Ironforge in the mountains. Beyond that are the Orc Badlands. Few come back from there.",
+				Choices: []models.DialogueChoice{
+					{Text: "Thank you, elder.", Next: "greeting"},
+				},
+			},
+		},
+	},
+	2: {
+		ID:          2,
+		Name:        "Marta the Shopkeeper",
+		Description: "Runs the general store and hears every rumour in the village",
+		Location:    "Cherry Village",
+		Start:       "greeting",
+		Nodes: map[string]models.DialogueNode{
+			"greeting": {
+				ID:   "greeting",
+				Text: "Hello, dear! Looking for something?",
+				Choices: []models.DialogueChoice{
+					{Text: "Show me your wares.", Next: "browsing",
+						Actions: []models.DialogueAction{{Type: models.ActionOpenShop, ShopID: 1}}},
+					{Text: "Heard any rumours?", Next: "rumours"},
+					{Text: "Just looking."},
+				},
+			},
+			"browsing": {
+				ID:   "browsing",
+				Text: "Take your time, everything's fairly priced.",
+				Choices: []models.DialogueChoice{
+					{Text: "Thanks, Marta."},
+				},
+			},
+			"rumours": {
+				ID:   "rumours",
+				Text: "They say Borin in Ironforge pays no attention to anyone who can't tell iron from tin. Learn your crafting first!",
+				Choices: []models.DialogueChoice{
+					{Text: "Good to know.", Next: "greeting"},
+				},
+			},
+		},
+	},
+	3: {
+		ID:          3,
+		Name:        "Hunter Kael",
+		Description: "A grizzled hunter who tracks the wolves of the woods",
+		Location:    "Whispering Woods",
+		Start:       "greeting",
+		Nodes: map[string]models.DialogueNode{
+			"greeting": {
+				ID:   "greeting",
+				Text: "Quiet. You'll scare off the game.",
+				Choices: []models.DialogueChoice{
+					{Text: "Need a hand with anything?", Next: "offer",
+						Conditions: []models.DialogueCondition{{Type: models.ConditionQuestState, QuestID: 2, State: models.QuestNone}}},
+					{Text: "The pack is thinned. Here are three pelts.", Next: "hunt_done",
+						Conditions: []models.DialogueCondition{
+							{Type: models.ConditionQuestState, QuestID: 2, State: models.QuestActive},
+							{Type: models.ConditionHasItem, ItemID: 6, Amount: 3},
+						},
+						Actions: []models.DialogueAction{
+							{Type: models.ActionCompleteQuest, QuestID: 2, ItemID: 6, Amount: 3},
+							{Type: models.ActionGiveItem, ItemID: 58, Amount: 1},
+							{Type: models.ActionGiveItem, ItemID: 60, Amount: 25},
+						}},
+					{Text: "I've got a wolf pelt for you.", Next: "pelt",
+						Conditions: []models.DialogueCondition{{Type: models.ConditionHasItem, ItemID: 6, Amount: 1}}},
+					{Text: "Sorry to bother you."},
+				},
+			},
+			"offer": {
+				ID:         "offer",
+				Text:       "The pack's grown bold. Hunt them down and I'll make it worth your while.",
+				Conditions: []models.DialogueCondition{{Type: models.ConditionSkillLevel, Skill: models.SkillCombat, Amount: 3}},
+				Else:       "too_weak",
+				Choices: []models.DialogueChoice{
+					{Text: "Consider it done.", Next: "accepted",
+						Actions: []models.DialogueAction{{Type: models.ActionStartQuest, QuestID: 2}}},
+					{Text: "Maybe later."},
+				},
+			},
+			"too_weak": {
+				ID:   "too_weak",
+				Text: "You? The wolves would make a meal of you. Come back when you've reached combat level 3.",
+				Choices: []models.DialogueChoice{
+					{Text: "I'll be back."},
+				},
+			},
+			"accepted": {
+				ID:   "accepted",
+				Text: "Good. Bring me three pelts as proof, and watch your flanks, they hunt in packs.",
+				Choices: []models.DialogueChoice{
+					{Text: "Understood."},
+				},
+			},
+			"hunt_done": {
+				ID:   "hunt_done",
+				Text: "Fine work. You'll have more use for my old longbow than I do now.",
+				Choices: []models.DialogueChoice{
+					{Text: "I'll put it to good use."},
+				},
+			},
+			"pelt": {
+				ID:   "pelt",
+				Text: "A fine pelt. The leatherworkers in Ironforge will pay well for that.",
+				Choices: []models.DialogueChoice{
+					{Text: "Thanks for the tip.", Next: "greeting"},
+				},
+			},
+		},
+	},
+	4: {
+		ID:          4,
+		Name:        "Borin the Smith",
+		Description: "Ironforge's master smith, gruff but fair",
+		Location:    "Ironforge",
+		Start:       "greeting",
+		Nodes: map[string]models.DialogueNode{
+			"greeting": {
+				ID:   "greeting",
+				Text: "Aye? Make it quick, the forge won't wait.",
+				Choices: []models.DialogueChoice{
+					{Text: "I want to trade.", Next: "trading",
+						Actions: []models.DialogueAction{{Type: models.ActionOpenShop, ShopID: 2}}},
+					{Text: "Can you teach me about smithing?", Next: "advice"},
+					{Text: "Never mind."},
+				},
+			},
+			"trading": {
+				ID:   "trading",
+				Text: "Best steel this side of the mountains.",
+				Choices: []models.DialogueChoice{
+					{Text: "Thanks, Borin."},
+				},
+			},
+			"advice": {
+				ID:         "advice",
+				Text:       "You've got the hands of a crafter. Steel needs coal, always one more than you think.",
+				Conditions: []models.DialogueCondition{{Type: models.ConditionSkillLevel, Skill: models.SkillCrafting, Amount: 10}},
+				Else:       "no_advice",
+				Choices: []models.DialogueChoice{
+					{Text: "I'll remember that.", Next: "greeting"},
+				},
+			},
+			"no_advice": {
+				ID:   "no_advice",
+				Text: "Come back when you can tell iron from tin. Crafting level 10, at least.",
+				Choices: []models.DialogueChoice{
+					{Text: "Fair enough.", Next: "greeting"},
+				},
+			},
+		},
+	},
+}
+
+// conversations holds each player's conversation in progress, keyed by player ID
+var conversations = map[uint]*models.Conversation{}
+
+// dialogueChoice is a reply the player can currently give, identified by its
+// position among all of the node's choices
+type dialogueChoice struct {
+	ID   int    `json:"id"`
+	Text string `json:"text"`
+}
+
+// dialogueView is what the NPC says and the replies open to the player
+func dialogueView(p *models.Player, node models.DialogueNode) gin.H {
+	choices := []dialogueChoice{}
+	for i, choice := range node.Choices {
+		if models.MeetsConditions(p, choice.Conditions) {
+			choices = append(choices, dialogueChoice{ID: i, Text: choice.Text})
+		}
+	}
+	return gin.H{"text": node.Text, "choices": choices}
+}
+
+// startQuest gives the player a quest they haven't started yet
+func startQuest(p *models.Player, questID uint) error {
+	if questID == 0 || int(questID) > len(availableQuests) {
+		return fmt.Errorf("quest not found")
+	}
+	name := availableQuests[questID-1]
+	switch p.QuestStatus(questID) {
+	case models.QuestActive:
+		return fmt.Errorf("you are already on the quest %s", name)
+	case models.QuestCompleted:
+		return fmt.Errorf("you have already completed the quest %s", name)
+	}
+
+	p.ActiveQuests = append(p.ActiveQuests, models.PlayerQuest{
+		PlayerID:  p.ID,
+		QuestID:   questID,
+		Status:    models.QuestActive,
+		StartedAt: time.Now(),
+	})
+	return nil
+}
+
+// completeQuest hands in the items a quest needs and moves it to the
+// player's completed quests
+func completeQuest(p *models.Player, questID, itemID uint, amount int) error {
+	if questID == 0 || int(questID) > len(availableQuests) {
+		return fmt.Errorf("quest not found")
+	}
+	name := availableQuests[questID-1]
+	if p.QuestStatus(questID) != models.QuestActive {
+		return fmt.Errorf("you are not on the quest %s", name)
+	}
+	if amount > 0 {
+		if !p.RemoveItemFromInventory(itemID, amount) {
+			return fmt.Errorf("you need %d %s to complete %s", amount, catalogItem(itemID, amount).Name, name)
+		}
+		recordItem(p.ID, models.SourceNPC, itemID, -amount)
+	}
+	p.CompleteQuest(questID, time.Now())
+	return nil
+}
+
+// validateDialogueActions checks every action of a choice can be carried out
+// before any of them are
+func validateDialogueActions(p *models.Player, actions []models.DialogueAction) error {
+	items := []models.InventoryItem{}
+	for _, action := range actions {
+		switch action.Type {
+		case models.ActionStartQuest:
+			if status := p.QuestStatus(action.QuestID); status != models.QuestNone {
+				return fmt.Errorf("you have already taken on this quest")
+			}
+		case models.ActionCompleteQuest:
+			if status := p.QuestStatus(action.QuestID); status != models.QuestActive {
+				return fmt.Errorf("you are not on this quest")
+			}
+			if p.ItemQuantity(action.ItemID) < action.Amount {
+				return fmt.Errorf("you don't have what this quest needs")
+			}
+		case models.ActionOpenShop:
+			if _, ok := shops[action.ShopID]; !ok {
+				return fmt.Errorf("shop not found")
+			}
+		case models.ActionGiveItem:
+			items = append(items, catalogItem(action.ItemID, action.Amount))
+		}
+	}
+	if !p.CanAddItems(items) {
+		return fmt.Errorf("not enough inventory space")
+	}
+	return nil
+}
+
+// applyDialogueActions carries out a choice's actions, returning a line for
+// each and the shop it opened, if any
+func applyDialogueActions(p *models.Player, actions []models.DialogueAction) ([]string, *models.Shop) {
+	messages := []string{}
+	var opened *models.Shop
+	for _, action := range actions {
+		switch action.Type {
+		case models.ActionStartQuest:
+			if err := startQuest(p, action.QuestID); err == nil {
+				messages = append(messages, fmt.Sprintf("Quest started: %s", availableQuests[action.QuestID-1]))
+			}
+		case models.ActionCompleteQuest:
+			if err := completeQuest(p, action.QuestID, action.ItemID, action.Amount); err == nil {
+				messages = append(messages, fmt.Sprintf("Quest completed: %s", availableQuests[action.QuestID-1]))
+				messages = describeAchievements(checkAchievements(p), messages)
+			}
+		case models.ActionOpenShop:
+			opened = shops[action.ShopID]
+			opened.Restock(time.Now())
+		case models.ActionGiveItem:
+			item := catalogItem(action.ItemID, action.Amount)
+			p.AddItemToInventory(item)
+			recordItem(p.ID, models.SourceNPC, item.ID, item.Quantity)
+			messages = append(messages, fmt.Sprintf("You received %dx %s", item.Quantity, item.Name))
+		}
+	}
+	return messages, opened
+}
+
+// npcsAt returns every NPC at a location, ordered by ID
+func npcsAt(location string) []*models.NPC {
+	list := []*models.NPC{}
+	for _, npc := range npcs {
+		if npc.Location == location {
+			list = append(list, npc)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list
+}
+
+func getNPCs(c *gin.Context) {
+	list := make([]*models.NPC, 0, len(npcs))
+	for _, npc := range npcs {
+		list = append(list, npc)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	c.JSON(http.StatusOK, list)
+}
+
+// talkToNPC steps through a conversation. Without a choice it starts over
+// from the NPC's greeting; with one it replies and moves to the next node.
+func talkToNPC(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+	npc, ok := findNPC(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		Choice *int `json:"choice"`
+	}
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&request); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+			return
+		}
+	}
+
+	if err := atLocation(p, npc.Location); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	conversation, ok := conversations[p.ID]
+	if request.Choice == nil || !ok || conversation.NPCID != npc.ID {
+		node, ok := npc.Resolve(p, npc.Start)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("%s has nothing to say", npc.Name)})
+			return
+		}
+		conversations[p.ID] = &models.Conversation{PlayerID: p.ID, NPCID: npc.ID, NodeID: node.ID, StartedAt: time.Now()}
+		c.JSON(http.StatusOK, gin.H{"npc": npc.Name, "dialogue": dialogueView(p, node), "ended": false})
+		return
+	}
+
+	node := npc.Nodes[conversation.NodeID]
+	index := *request.Choice
+	if index < 0 || index >= len(node.Choices) || !models.MeetsConditions(p, node.Choices[index].Conditions) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "That choice isn't available"})
+		return
+	}
+	choice := node.Choices[index]
+	if err := validateDialogueActions(p, choice.Actions); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	messages, shop := applyDialogueActions(p, choice.Actions)
+	response := gin.H{"npc": npc.Name, "messages": messages, "player": p}
+	if shop != nil {
+		response["shop"] = shop
+	}
+
+	next, ok := npc.Resolve(p, choice.Next)
+	if choice.Next == "" || !ok {
+		delete(conversations, p.ID)
+		response["ended"] = true
+		c.JSON(http.StatusOK, response)
+		return
+	}
+	conversation.NodeID = next.ID
+	response["dialogue"] = dialogueView(p, next)
+	response["ended"] = false
+	c.JSON(http.StatusOK, response)
+}
+
+// findNPC looks up the NPC named by the :id route parameter, writing an error response if it doesn't exist
+func findNPC(c *gin.Context) (*models.NPC, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid NPC ID"})
+		return nil, false
+	}
+
+	npc, ok := npcs[uint(id)]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "NPC not found"})
+		return nil, false
+	}
+	return npc, true
+}
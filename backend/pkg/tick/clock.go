@@ -0,0 +1,52 @@
+package tick
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the scheduler what time it is
+type Clock interface {
+	Now() time.Time
+}
+
+// RealClock reads the system clock
+type RealClock struct{}
+
+// Now returns the current system time
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// FakeClock is a clock that only moves when told to, so ticks can be driven
+// by hand
+type FakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+// NewFakeClock returns a fake clock stopped at the given time
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the fake clock's current time
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Advance moves the fake clock forward by d
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Set moves the fake clock to the given time
+func (c *FakeClock) Set(now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = now
+}
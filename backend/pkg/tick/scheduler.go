@@ -0,0 +1,199 @@
+// Package tick runs the game's time-based systems on a fixed tick, so things
+// like regeneration and respawns happen whether or not anyone sends a request.
+package tick
+
+import (
+	"errors"
+	"log"
+	"sync"
+	"time"
+)
+
+// Tick is one step of game time
+type Tick struct {
+	Number int64     `json:"number"`
+	Time   time.Time `json:"time"`
+}
+
+// Store persists the last processed tick so a restarted server can catch up
+// on the ticks it missed
+type Store interface {
+	// Load returns the last saved tick, or false if none has been saved
+	Load() (Tick, bool, error)
+	Save(Tick) error
+}
+
+// Config controls how often ticks happen and how far behind the scheduler
+// will catch up
+type Config struct {
+	Rate time.Duration
+	// MaxCatchUp limits how much missed time is replayed after a restart or
+	// when the scheduler falls behind. Older ticks are skipped. Zero replays
+	// everything.
+	MaxCatchUp time.Duration
+}
+
+// system is a registered function run every tick, or at most once per interval
+type system struct {
+	name   string
+	every  time.Duration
+	run    func(Tick)
+	nextAt time.Time
+}
+
+// Scheduler runs registered systems once per tick. Ticks are spaced exactly
+// Rate apart in game time, so ticks missed while the server was busy or down
+// are run back to back until it has caught up.
+type Scheduler struct {
+	rate       time.Duration
+	maxCatchUp time.Duration
+	clock      Clock
+	store      Store
+	locker     sync.Locker
+
+	mu      sync.Mutex
+	systems []*system
+	last    Tick
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// New creates a scheduler. The store and locker are optional. When a locker
+// is given it is held while each tick's systems run.
+func New(config Config, clock Clock, store Store, locker sync.Locker) (*Scheduler, error) {
+	if config.Rate <= 0 {
+		return nil, errors.New("tick rate must be positive")
+	}
+	if config.MaxCatchUp < 0 {
+		return nil, errors.New("max catch up must not be negative")
+	}
+	return &Scheduler{
+		rate:       config.Rate,
+		maxCatchUp: config.MaxCatchUp,
+		clock:      clock,
+		store:      store,
+		locker:     locker,
+	}, nil
+}
+
+// Register adds a system to run on every tick, or no more than once per every
+// when every is longer than the tick rate. Systems run in the order they
+// were registered.
+func (s *Scheduler) Register(name string, every time.Duration, run func(Tick)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.systems = append(s.systems, &system{name: name, every: every, run: run})
+}
+
+// Rate returns the time between ticks
+func (s *Scheduler) Rate() time.Duration {
+	return s.rate
+}
+
+// LastTick returns the most recently processed tick
+func (s *Scheduler) LastTick() Tick {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
+
+// Resume picks up from the tick saved in the store, so the next call to
+// RunDue catches up on everything missed since, up to MaxCatchUp. Without a
+// saved tick the scheduler starts from the clock's current time.
+func (s *Scheduler) Resume() error {
+	if s.store == nil {
+		return nil
+	}
+	last, ok, err := s.store.Load()
+	if err != nil || !ok {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.last = last
+	return nil
+}
+
+// RunDue runs every tick that is due by now, returning how many ran.
+// The last tick run is saved to the store afterwards.
+func (s *Scheduler) RunDue(now time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.last.Time.IsZero() {
+		s.last.Time = now
+		return 0, nil
+	}
+
+	if behind := now.Sub(s.last.Time); s.maxCatchUp > 0 && behind > s.maxCatchUp {
+		skipped := int64((behind - s.maxCatchUp) / s.rate)
+		s.last.Number += skipped
+		s.last.Time = s.last.Time.Add(time.Duration(skipped) * s.rate)
+		log.Printf("Skipped %d ticks older than %s", skipped, s.maxCatchUp)
+	}
+
+	ran := 0
+	for next := s.last.Time.Add(s.rate); !next.After(now); next = s.last.Time.Add(s.rate) {
+		t := Tick{Number: s.last.Number + 1, Time: next}
+		s.runSystems(t)
+		s.last = t
+		ran++
+	}
+
+	if ran > 0 && s.store != nil {
+		if err := s.store.Save(s.last); err != nil {
+			return ran, err
+		}
+	}
+	return ran, nil
+}
+
+// runSystems runs each system that is due on the tick
+func (s *Scheduler) runSystems(t Tick) {
+	if s.locker != nil {
+		s.locker.Lock()
+		defer s.locker.Unlock()
+	}
+
+	for _, sys := range s.systems {
+		if t.Time.Before(sys.nextAt) {
+			continue
+		}
+		sys.run(t)
+		sys.nextAt = t.Time.Add(sys.every)
+	}
+}
+
+// Start catches up on missed ticks and then keeps running ticks in the
+// background until Stop is called
+func (s *Scheduler) Start() {
+	s.stop = make(chan struct{})
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.rate)
+		defer ticker.Stop()
+		for {
+			if ran, err := s.RunDue(s.clock.Now()); err != nil {
+				log.Printf("Failed to save tick after running %d ticks: %v", ran, err)
+			}
+
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop waits for the tick in progress to finish and stops the scheduler.
+// The last processed tick has already been saved by then.
+func (s *Scheduler) Stop() {
+	close(s.stop)
+	<-s.done
+}
@@ -0,0 +1,143 @@
+package tick
+
+import (
+	"testing"
+	"time"
+)
+
+// memoryStore keeps the saved tick in memory
+type memoryStore struct {
+	tick  Tick
+	saved bool
+}
+
+func (m *memoryStore) Load() (Tick, bool, error) {
+	return m.tick, m.saved, nil
+}
+
+func (m *memoryStore) Save(t Tick) error {
+	m.tick, m.saved = t, true
+	return nil
+}
+
+var start = time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+func newTestScheduler(t *testing.T, config Config, clock Clock, store Store) *Scheduler {
+	t.Helper()
+	scheduler, err := New(config, clock, store, nil)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return scheduler
+}
+
+func runDue(t *testing.T, scheduler *Scheduler, now time.Time) int {
+	t.Helper()
+	ran, err := scheduler.RunDue(now)
+	if err != nil {
+		t.Fatalf("RunDue: %v", err)
+	}
+	return ran
+}
+
+func TestRunDueCatchesUpOnMissedTicks(t *testing.T) {
+	clock := NewFakeClock(start)
+	store := &memoryStore{}
+	scheduler := newTestScheduler(t, Config{Rate: time.Second}, clock, store)
+
+	var ticks []int64
+	scheduler.Register("count", 0, func(t Tick) { ticks = append(ticks, t.Number) })
+
+	if ran := runDue(t, scheduler, clock.Now()); ran != 0 {
+		t.Fatalf("first RunDue ran %d ticks, want 0", ran)
+	}
+
+	clock.Advance(5*time.Second + 500*time.Millisecond)
+	if ran := runDue(t, scheduler, clock.Now()); ran != 5 {
+		t.Fatalf("RunDue ran %d ticks, want 5", ran)
+	}
+	for i, number := range ticks {
+		if number != int64(i+1) {
+			t.Fatalf("ticks ran out of order: %v", ticks)
+		}
+	}
+
+	last := scheduler.LastTick()
+	if last.Number != 5 || !last.Time.Equal(start.Add(5*time.Second)) {
+		t.Errorf("last tick = %+v, want number 5 at %v", last, start.Add(5*time.Second))
+	}
+	if store.tick != last {
+		t.Errorf("saved tick = %+v, want %+v", store.tick, last)
+	}
+}
+
+func TestRunDueSkipsTicksBeyondMaxCatchUp(t *testing.T) {
+	clock := NewFakeClock(start)
+	scheduler := newTestScheduler(t, Config{Rate: time.Second, MaxCatchUp: 10 * time.Second}, clock, nil)
+
+	runs := 0
+	scheduler.Register("count", 0, func(Tick) { runs++ })
+	runDue(t, scheduler, clock.Now())
+
+	clock.Advance(time.Minute)
+	if ran := runDue(t, scheduler, clock.Now()); ran != 10 {
+		t.Fatalf("RunDue ran %d ticks, want 10", ran)
+	}
+	if runs != 10 {
+		t.Errorf("system ran %d times, want 10", runs)
+	}
+	if number := scheduler.LastTick().Number; number != 60 {
+		t.Errorf("last tick number = %d, want 60 so skipped ticks are still counted", number)
+	}
+}
+
+func TestSystemsRunNoMoreThanEveryInterval(t *testing.T) {
+	clock := NewFakeClock(start)
+	scheduler := newTestScheduler(t, Config{Rate: time.Second}, clock, nil)
+
+	var every, always []int64
+	scheduler.Register("every", 3*time.Second, func(t Tick) { every = append(every, t.Number) })
+	scheduler.Register("always", 0, func(t Tick) { always = append(always, t.Number) })
+	runDue(t, scheduler, clock.Now())
+
+	clock.Advance(9 * time.Second)
+	runDue(t, scheduler, clock.Now())
+
+	want := []int64{1, 4, 7}
+	if len(every) != len(want) {
+		t.Fatalf("interval system ran on ticks %v, want %v", every, want)
+	}
+	for i := range want {
+		if every[i] != want[i] {
+			t.Fatalf("interval system ran on ticks %v, want %v", every, want)
+		}
+	}
+	if len(always) != 9 {
+		t.Errorf("system without an interval ran %d times, want 9", len(always))
+	}
+}
+
+func TestResumeCatchesUpOnDowntime(t *testing.T) {
+	clock := NewFakeClock(start.Add(time.Hour + 500*time.Millisecond))
+	store := &memoryStore{tick: Tick{Number: 100, Time: start}, saved: true}
+	scheduler := newTestScheduler(t, Config{Rate: time.Second, MaxCatchUp: 10 * time.Minute}, clock, store)
+
+	runs := 0
+	scheduler.Register("count", 0, func(Tick) { runs++ })
+	if err := scheduler.Resume(); err != nil {
+		t.Fatalf("Resume: %v", err)
+	}
+	if last := scheduler.LastTick(); last != store.tick {
+		t.Fatalf("resumed at %+v, want the saved tick %+v", last, store.tick)
+	}
+
+	if ran := runDue(t, scheduler, clock.Now()); ran != 600 {
+		t.Fatalf("RunDue replayed %d ticks missed while down, want 600", ran)
+	}
+	if runs != 600 {
+		t.Errorf("system ran %d times, want 600", runs)
+	}
+	if store.tick.Number != 3700 || !store.tick.Time.Equal(start.Add(time.Hour)) {
+		t.Errorf("saved tick = %+v, want number 3700 at %v", store.tick, start.Add(time.Hour))
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"galycherrygame/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// players holds every player in the world, keyed by player ID. The hero is
+// the default player for requests that don't name one.
+var players = map[uint]*models.Player{
+	player.ID: &player,
+}
+
+var nextPlayerID = player.ID + 1
+
+// newPlayer returns a fresh level 1 character with the starting stats and gold
+func newPlayer(id uint, name string) *models.Player {
+	return &models.Player{
+		ID:                id,
+		Name:              name,
+		Health:            100,
+		MaxHealth:         100,
+		Stamina:           100,
+		MaxStamina:        100,
+		Location:          startingLocation,
+		Home:              startingLocation,
+		CombatStyle:       models.StyleMelee,
+		Level:             1,
+		ExperienceToLevel: progression.ExperienceToLevel(1),
+		Gold:              50,
+		SkillCap:          99,
+		Skills: models.PlayerSkills{
+			Combat:   1,
+			Fishing:  1,
+			Cooking:  1,
+			Farming:  1,
+			Crafting: 1,
+			Alchemy:  1,
+			// Gathering skills
+			Mining:      1,
+			Woodcutting: 1,
+		},
+	}
+}
+
+func createPlayer(c *gin.Context) {
+	var request struct {
+		Name string `json:"name" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	created := newPlayer(nextPlayerID, request.Name)
+	players[created.ID] = created
+	nextPlayerID++
+
+	c.JSON(http.StatusCreated, created)
+}
+
+// currentPlayer returns the player making the request, identified by the
+// X-Player-ID header and defaulting to the hero. It writes an error response
+// if the header names an unknown player.
+func currentPlayer(c *gin.Context) (*models.Player, bool) {
+	header := c.GetHeader("X-Player-ID")
+	if header == "" {
+		return &player, true
+	}
+
+	id, err := strconv.ParseUint(header, 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid player ID"})
+		return nil, false
+	}
+
+	p, ok := players[uint(id)]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+		return nil, false
+	}
+	return p, true
+}
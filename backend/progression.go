@@ -0,0 +1,46 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"galycherrygame/backend/models"
+)
+
+// progression is the level curve and per level rewards every player advances along
+var progression = models.DefaultProgression
+
+// loadProgressionConfig replaces the progression settings with those in a
+// JSON file. Fields missing from the file keep their default values.
+func loadProgressionConfig(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	config := models.DefaultProgression
+	if err := json.Unmarshal(data, &config); err != nil {
+		return err
+	}
+	if err := config.Validate(); err != nil {
+		return fmt.Errorf("invalid progression config: %w", err)
+	}
+
+	progression = config
+	return nil
+}
+
+// grantExperience awards character experience using the configured progression
+func grantExperience(p *models.Player, amount int) models.LevelUp {
+	return p.GainExperience(amount, progression)
+}
+
+// describeLevelUp returns a combat log line for the levels gained, if any
+func describeLevelUp(levelUp models.LevelUp) (string, bool) {
+	if levelUp.Levels == 0 {
+		return "", false
+	}
+	return fmt.Sprintf("Level Up! You are now level %d: +%d max health, +%d max stamina, +%d skill points",
+		levelUp.Level, levelUp.MaxHealth, levelUp.MaxStamina, levelUp.SkillPoints), true
+}
@@ -0,0 +1,162 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"galycherrygame/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// combatTimeout is how long after their last fight a player counts as being in combat
+const combatTimeout = 10 * time.Second
+
+const (
+	// healSpellRune is the item used up by each cast of the healing spell
+	healSpellRune = 65
+	// healSpellStamina is the stamina each cast of the healing spell costs
+	healSpellStamina = 15
+)
+
+// inns holds every inn where players can rest, keyed by inn ID
+var inns = map[uint]models.Inn{
+	1: {ID: 1, Name: "The Cherry Blossom Inn", Location: "Cherry Village", Cost: 20},
+	2: {ID: 2, Name: "The Anvil and Ale", Location: "Ironforge", Cost: 50},
+}
+
+// lastCombat holds when each player last fought, keyed by player ID
+var lastCombat = map[uint]time.Time{}
+
+// markCombat records that the player is fighting, pausing their natural health regeneration
+func markCombat(p *models.Player, now time.Time) {
+	lastCombat[p.ID] = now
+	updateRegeneration(p, now)
+}
+
+// inCombat reports whether the player has fought recently or is facing an
+// enemy in a dungeon
+func inCombat(p *models.Player, now time.Time) bool {
+	if run, ok := dungeonRuns[p.ID]; ok && run.Status == models.RunActive && run.Enemy != nil {
+		return true
+	}
+	foughtAt, ok := lastCombat[p.ID]
+	return ok && now.Sub(foughtAt) < combatTimeout
+}
+
+// updateRegeneration refreshes the player's reported regeneration rates
+func updateRegeneration(p *models.Player, now time.Time) {
+	p.Regeneration = p.RegenerationRate(inCombat(p, now))
+}
+
+// regenerate restores the health and stamina the player recovers naturally each regeneration interval
+func regenerate(p *models.Player, now time.Time) {
+	updateRegeneration(p, now)
+	p.Recover(p.Regeneration.Health, p.Regeneration.Stamina)
+}
+
+func getInns(c *gin.Context) {
+	list := make([]models.Inn, 0, len(inns))
+	for _, inn := range inns {
+		list = append(list, inn)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	c.JSON(http.StatusOK, list)
+}
+
+// restAtInn pays for a room and fully restores the player's health and stamina
+func restAtInn(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid inn ID"})
+		return
+	}
+	inn, ok := inns[uint(id)]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Inn not found"})
+		return
+	}
+
+	if err := atLocation(p, inn.Location); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+	now := time.Now()
+	if inCombat(p, now) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot rest while in combat"})
+		return
+	}
+	if p.IsFullyRested() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You are already fully rested"})
+		return
+	}
+	if p.Gold < inn.Cost {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("A room at %s costs %d gold (you have %d)", inn.Name, inn.Cost, p.Gold),
+		})
+		return
+	}
+
+	p.Gold -= inn.Cost
+	recordGold(p.ID, models.SourceInn, -inn.Cost)
+	health, stamina := p.Recover(p.MaxHealth, p.MaxStamina)
+	updateRegeneration(p, now)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("You rest at %s and recover %d health and %d stamina", inn.Name, health, stamina),
+		"player":  p,
+	})
+}
+
+// healSpellAmount returns how much health the healing spell restores, which
+// grows with the player's magic and the magic power of their weapon
+func healSpellAmount(p *models.Player) int {
+	amount := 10 + p.Magic*2
+	if p.EquippedWeapon != nil {
+		amount += p.EquippedWeapon.Stats.MagicPower
+	}
+	return amount
+}
+
+// castHeal spends a nature rune and some stamina to restore the player's
+// health. Unlike resting it can be cast in the middle of a fight.
+func castHeal(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	if p.Health >= p.MaxHealth {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You are already at full health"})
+		return
+	}
+	if p.ItemQuantity(healSpellRune) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You need a Nature Rune to cast Heal"})
+		return
+	}
+	if p.Stamina < healSpellStamina {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Casting Heal takes %d stamina (you have %d)", healSpellStamina, p.Stamina),
+		})
+		return
+	}
+
+	p.RemoveItemFromInventory(healSpellRune, 1)
+	recordItem(p.ID, models.SourceSpell, healSpellRune, -1)
+	p.Stamina -= healSpellStamina
+	healed := p.Heal(healSpellAmount(p))
+	updateRegeneration(p, time.Now())
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("You cast Heal and restore %d health", healed),
+		"player":  p,
+	})
+}
@@ -0,0 +1,221 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"galycherrygame/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// resourceNodes holds every rock and tree in the world, keyed by node ID
+var resourceNodes = map[uint]*models.ResourceNode{
+	1: {ID: 1, Name: "Copper Rocks", Skill: models.SkillMining, Location: "Cherry Village", Level: 1, Experience: 17, ItemID: 47, BaseDuration: 8 * time.Second, Charges: 3, MaxCharges: 3, RespawnTime: 20 * time.Second},
+	2: {ID: 2, Name: "Tin Rocks", Skill: models.SkillMining, Location: "Cherry Village", Level: 1, Experience: 17, ItemID: 48, BaseDuration: 8 * time.Second, Charges: 3, MaxCharges: 3, RespawnTime: 20 * time.Second},
+	3: {ID: 3, Name: "Iron Rocks", Skill: models.SkillMining, Location: "Ironforge", Level: 15, Experience: 35, ItemID: 49, BaseDuration: 10 * time.Second, Charges: 2, MaxCharges: 2, RespawnTime: 45 * time.Second},
+	4: {ID: 4, Name: "Coal Rocks", Skill: models.SkillMining, Location: "Ironforge", Level: 30, Experience: 50, ItemID: 50, BaseDuration: 12 * time.Second, Charges: 2, MaxCharges: 2, RespawnTime: time.Minute},
+	5: {ID: 5, Name: "Tree", Skill: models.SkillWoodcutting, Location: "Cherry Village", Level: 1, Experience: 25, ItemID: 51, BaseDuration: 6 * time.Second, Charges: 4, MaxCharges: 4, RespawnTime: 30 * time.Second},
+	6: {ID: 6, Name: "Oak Tree", Skill: models.SkillWoodcutting, Location: "Cherry Village", Level: 15, Experience: 37, ItemID: 52, BaseDuration: 8 * time.Second, Charges: 3, MaxCharges: 3, RespawnTime: 45 * time.Second},
+	7: {ID: 7, Name: "Willow Tree", Skill: models.SkillWoodcutting, Location: "Ironforge", Level: 30, Experience: 67, ItemID: 53, BaseDuration: 10 * time.Second, Charges: 5, MaxCharges: 5, RespawnTime: time.Minute},
+}
+
+// gatheringTools lists every pickaxe and axe from worst to best
+var gatheringTools = []models.GatheringTool{
+	{ItemID: 41, Skill: models.SkillMining, Level: 1, Speed: 1},
+	{ItemID: 42, Skill: models.SkillMining, Level: 15, Speed: 1.5},
+	{ItemID: 43, Skill: models.SkillMining, Level: 30, Speed: 2},
+	{ItemID: 44, Skill: models.SkillWoodcutting, Level: 1, Speed: 1},
+	{ItemID: 45, Skill: models.SkillWoodcutting, Level: 15, Speed: 1.5},
+	{ItemID: 46, Skill: models.SkillWoodcutting, Level: 30, Speed: 2},
+}
+
+func getResourceNodes(c *gin.Context) {
+	now := time.Now()
+	nodes := []*models.ResourceNode{}
+	for _, node := range resourceNodes {
+		node.Update(now)
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	c.JSON(http.StatusOK, nodes)
+}
+
+// startGatheringNode begins mining a rock or chopping a tree with the best tool the player can use
+func startGatheringNode(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+	node, ok := findResourceNode(c)
+	if !ok {
+		return
+	}
+
+	tool, err := canGatherNode(p, node)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	now := time.Now()
+	node.Update(now)
+	if node.IsDepleted() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("%s is depleted", node.Name),
+		})
+		return
+	}
+
+	action := &models.GatheringAction{
+		PlayerID:  p.ID,
+		Skill:     node.Skill,
+		SourceID:  node.ID,
+		Source:    node.Name,
+		StartedAt: now,
+		Duration:  node.GatherDuration(tool),
+	}
+	if !startGathering(c, action) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("You start %s the %s with your %s", gatheringVerb(node.Skill), node.Name, itemCatalog[tool.ItemID].Name),
+		"player":  p,
+		"action":  action,
+	})
+}
+
+// collectResource gathers from the node once the player's action has finished,
+// as long as nobody else has depleted it in the meantime
+func collectResource(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+	action, ok := finishedGathering(c, p, models.SkillMining, models.SkillWoodcutting)
+	if !ok {
+		return
+	}
+
+	node := resourceNodes[action.SourceID]
+	item, levels, err := gatherFromNode(p, node, time.Now())
+	if errors.Is(err, errNodeDepleted) {
+		delete(gatheringActions, p.ID)
+		c.JSON(http.StatusOK, gin.H{
+			"message": fmt.Sprintf("%s was depleted before you finished", node.Name),
+			"player":  p,
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+	delete(gatheringActions, p.ID)
+
+	message := fmt.Sprintf("You get some %s", item.Name)
+	if levels > 0 {
+		message += fmt.Sprintf(". Your %s level is now %d!", node.Skill, p.SkillLevel(node.Skill))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message":    message,
+		"player":     p,
+		"item":       item,
+		"experience": node.Experience,
+		"node":       node,
+	})
+}
+
+// errNodeDepleted is returned by gatherFromNode when the node has no charges left
+var errNodeDepleted = errors.New("resource is depleted")
+
+// gatherFromNode takes one resource from a node into the player's inventory,
+// returning the item and the skill levels gained
+func gatherFromNode(p *models.Player, node *models.ResourceNode, now time.Time) (models.InventoryItem, int, error) {
+	if !p.CanAddItem(node.ItemID) {
+		return models.InventoryItem{}, 0, errors.New("not enough inventory space")
+	}
+	if !node.Gather(now) {
+		return models.InventoryItem{}, 0, errNodeDepleted
+	}
+
+	item := catalogItem(node.ItemID, 1)
+	p.AddItemToInventory(item)
+	recordItem(p.ID, gatheringSource(node.Skill), item.ID, 1)
+	levels := p.AddSkillExperience(node.Skill, node.Experience)
+	return item, levels, nil
+}
+
+// canGatherNode checks the player is at the node and has the level and a tool
+// to gather from it, returning the best tool they can use
+func canGatherNode(p *models.Player, node *models.ResourceNode) (models.GatheringTool, error) {
+	if err := atLocation(p, node.Location); err != nil {
+		return models.GatheringTool{}, err
+	}
+	level := p.SkillLevel(node.Skill)
+	if level < node.Level {
+		return models.GatheringTool{}, fmt.Errorf("%s level %d required (current: %d)", skillTitle(node.Skill), node.Level, level)
+	}
+	tool, ok := bestTool(p, node.Skill)
+	if !ok {
+		return models.GatheringTool{}, fmt.Errorf("you need a tool you can use for %s", node.Skill)
+	}
+	return tool, nil
+}
+
+// bestTool returns the fastest tool for the skill that the player carries and has the level for
+func bestTool(p *models.Player, skill string) (models.GatheringTool, bool) {
+	level := p.SkillLevel(skill)
+	var best models.GatheringTool
+	found := false
+	for _, tool := range gatheringTools {
+		if tool.Skill != skill || tool.Level > level || p.ItemQuantity(tool.ItemID) == 0 {
+			continue
+		}
+		if !found || tool.Speed > best.Speed {
+			best, found = tool, true
+		}
+	}
+	return best, found
+}
+
+// skillTitle returns a gathering skill's name for the start of a sentence
+func skillTitle(skill string) string {
+	switch skill {
+	case models.SkillMining:
+		return "Mining"
+	case models.SkillWoodcutting:
+		return "Woodcutting"
+	}
+	return skill
+}
+
+// gatheringSource returns the ledger source for resources gathered with a skill
+func gatheringSource(skill string) string {
+	if skill == models.SkillWoodcutting {
+		return models.SourceWoodcutting
+	}
+	return models.SourceMining
+}
+
+// findResourceNode looks up the node named by the :id route parameter, writing an error response if it doesn't exist
+func findResourceNode(c *gin.Context) (*models.ResourceNode, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid resource ID"})
+		return nil, false
+	}
+
+	node, ok := resourceNodes[uint(id)]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Resource not found"})
+		return nil, false
+	}
+	return node, true
+}
@@ -0,0 +1,271 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"galycherrygame/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// shops holds every NPC shop, keyed by shop ID
+var shops = map[uint]*models.Shop{
+	1: {
+		ID:       1,
+		Name:     "Cherry Village General Store",
+		NPC:      "Marta the Shopkeeper",
+		Location: "Cherry Village",
+		Items: []models.ShopItem{
+			{Item: catalogItem(3, 1), BuyPrice: 25, SellPrice: 10, Stock: 10, MaxStock: 10, RestockInterval: 2 * time.Minute},
+			{Item: catalogItem(64, 1), BuyPrice: 40, SellPrice: 15, Stock: 5, MaxStock: 5, RestockInterval: 5 * time.Minute},
+			{Item: catalogItem(4, 1), BuyPrice: 5, SellPrice: 1, Stock: 50, MaxStock: 50, RestockInterval: 30 * time.Second},
+			{Item: catalogItem(5, 1), BuyPrice: 8, SellPrice: 3, Stock: 0, MaxStock: 20, RestockInterval: 5 * time.Minute},
+			{Item: catalogItem(6, 1), BuyPrice: 20, SellPrice: 8, Stock: 5, MaxStock: 5, RestockInterval: 5 * time.Minute},
+			{Item: catalogItem(14, 1), BuyPrice: 15, SellPrice: 5, Stock: 5, MaxStock: 5, RestockInterval: 5 * time.Minute},
+			{Item: catalogItem(15, 1), BuyPrice: 2, SellPrice: 1, Stock: 200, MaxStock: 200, RestockInterval: 30 * time.Second},
+			{Item: catalogItem(16, 1), BuyPrice: 6, SellPrice: 2, Stock: 0, MaxStock: 50, RestockInterval: 5 * time.Minute},
+			{Item: catalogItem(28, 1), BuyPrice: 4, SellPrice: 1, Stock: 30, MaxStock: 30, RestockInterval: time.Minute},
+			{Item: catalogItem(29, 1), BuyPrice: 6, SellPrice: 2, Stock: 30, MaxStock: 30, RestockInterval: time.Minute},
+			{Item: catalogItem(30, 1), BuyPrice: 10, SellPrice: 3, Stock: 20, MaxStock: 20, RestockInterval: 2 * time.Minute},
+			{Item: catalogItem(31, 1), BuyPrice: 30, SellPrice: 10, Stock: 5, MaxStock: 5, RestockInterval: 10 * time.Minute},
+			{Item: catalogItem(36, 1), BuyPrice: 8, SellPrice: 2, Stock: 20, MaxStock: 20, RestockInterval: 2 * time.Minute},
+			{Item: catalogItem(37, 1), BuyPrice: 25, SellPrice: 8, Stock: 10, MaxStock: 10, RestockInterval: 5 * time.Minute},
+			{Item: catalogItem(38, 1), BuyPrice: 12, SellPrice: 4, Stock: 5, MaxStock: 5, RestockInterval: 5 * time.Minute},
+			{Item: catalogItem(41, 1), BuyPrice: 20, SellPrice: 6, Stock: 5, MaxStock: 5, RestockInterval: 5 * time.Minute},
+			{Item: catalogItem(44, 1), BuyPrice: 20, SellPrice: 6, Stock: 5, MaxStock: 5, RestockInterval: 5 * time.Minute},
+			{Item: catalogItem(57, 1), BuyPrice: 40, SellPrice: 15, Stock: 3, MaxStock: 3, RestockInterval: 10 * time.Minute},
+			{Item: catalogItem(59, 1), BuyPrice: 45, SellPrice: 18, Stock: 3, MaxStock: 3, RestockInterval: 10 * time.Minute},
+			{Item: catalogItem(60, 1), BuyPrice: 2, SellPrice: 1, Stock: 500, MaxStock: 500, RestockInterval: 5 * time.Second},
+			{Item: catalogItem(62, 1), BuyPrice: 3, SellPrice: 1, Stock: 300, MaxStock: 300, RestockInterval: 10 * time.Second},
+			{Item: catalogItem(65, 1), BuyPrice: 8, SellPrice: 3, Stock: 100, MaxStock: 100, RestockInterval: 30 * time.Second},
+		},
+	},
+	2: {
+		ID:       2,
+		Name:     "Ironforge Smithy",
+		NPC:      "Borin the Smith",
+		Location: "Ironforge",
+		Repairs:  true,
+		Items: []models.ShopItem{
+			{Item: catalogItem(1, 1), BuyPrice: 60, SellPrice: 25, Stock: 3, MaxStock: 3, RestockInterval: 10 * time.Minute},
+			{Item: catalogItem(2, 1), BuyPrice: 50, SellPrice: 20, Stock: 3, MaxStock: 3, RestockInterval: 10 * time.Minute},
+			{Item: catalogItem(8, 1), BuyPrice: 400, SellPrice: 160, Stock: 1, MaxStock: 1, RestockInterval: time.Hour},
+			{Item: catalogItem(9, 1), BuyPrice: 350, SellPrice: 140, Stock: 1, MaxStock: 1, RestockInterval: time.Hour},
+			{Item: catalogItem(7, 1), BuyPrice: 40, SellPrice: 15, Stock: 0, MaxStock: 10, RestockInterval: 10 * time.Minute},
+			{Item: catalogItem(58, 1), BuyPrice: 300, SellPrice: 120, Stock: 1, MaxStock: 1, RestockInterval: time.Hour},
+			{Item: catalogItem(61, 1), BuyPrice: 4, SellPrice: 1, Stock: 300, MaxStock: 300, RestockInterval: 10 * time.Second},
+			{Item: catalogItem(63, 1), BuyPrice: 12, SellPrice: 4, Stock: 100, MaxStock: 100, RestockInterval: 30 * time.Second},
+		},
+	},
+}
+
+// shopTradeRequest is the body of a buy or sell request
+type shopTradeRequest struct {
+	ItemID   uint `json:"itemId" binding:"required"`
+	Quantity int  `json:"quantity" binding:"required,min=1"`
+}
+
+func getShopItems(c *gin.Context) {
+	now := time.Now()
+	for _, shop := range shops {
+		shop.Restock(now)
+	}
+	c.JSON(http.StatusOK, shops)
+}
+
+func getShop(c *gin.Context) {
+	shop, ok := findShop(c)
+	if !ok {
+		return
+	}
+	shop.Restock(time.Now())
+	c.JSON(http.StatusOK, shop)
+}
+
+func buyFromShop(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	shop, ok := findShop(c)
+	if !ok {
+		return
+	}
+	if err := atLocation(p, shop.Location); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	var request shopTradeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	shop.Restock(time.Now())
+
+	listing := shop.FindItem(request.ItemID)
+	if listing == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": fmt.Sprintf("%s does not sell that item", shop.Name)})
+		return
+	}
+
+	// Validate everything before touching gold or inventory so a failed
+	// purchase never leaves the player half charged
+	if listing.Stock < request.Quantity {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Only %d %s in stock", listing.Stock, listing.Item.Name),
+		})
+		return
+	}
+
+	cost := listing.BuyPrice * request.Quantity
+	if p.Gold < cost {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Insufficient gold: %d required (current: %d)", cost, p.Gold),
+		})
+		return
+	}
+
+	if !p.CanAddItem(listing.Item.ID) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Not enough inventory space"})
+		return
+	}
+
+	p.Gold -= cost
+	listing.Stock -= request.Quantity
+	p.AddItemToInventory(catalogItem(listing.Item.ID, request.Quantity))
+	recordGold(p.ID, models.SourceShopBuy, -cost)
+	recordItem(p.ID, models.SourceShopBuy, listing.Item.ID, request.Quantity)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Bought %d %s for %d gold", request.Quantity, listing.Item.Name, cost),
+		"player":  p,
+		"shop":    shop,
+	})
+}
+
+func sellToShop(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	shop, ok := findShop(c)
+	if !ok {
+		return
+	}
+	if err := atLocation(p, shop.Location); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	var request shopTradeRequest
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	shop.Restock(time.Now())
+
+	listing := shop.FindItem(request.ItemID)
+	if listing == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s does not buy that item", shop.Name)})
+		return
+	}
+
+	if p.ItemQuantity(request.ItemID) < request.Quantity {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("You do not have %d %s to sell", request.Quantity, listing.Item.Name),
+		})
+		return
+	}
+
+	earned := listing.SellPrice * request.Quantity
+	p.RemoveItemFromInventory(request.ItemID, request.Quantity)
+	p.Gold += earned
+	listing.Stock += request.Quantity
+	recordGold(p.ID, models.SourceShopSell, earned)
+	recordItem(p.ID, models.SourceShopSell, request.ItemID, -request.Quantity)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("Sold %d %s for %d gold", request.Quantity, listing.Item.Name, earned),
+		"player":  p,
+		"shop":    shop,
+	})
+}
+
+// repairCost returns the gold charged to restore the given durability, a
+// gold for every two points
+func repairCost(durability int) int {
+	return (durability + 1) / 2
+}
+
+// repairWeapon pays the shopkeeper to restore the equipped weapon's durability
+func repairWeapon(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	shop, ok := findShop(c)
+	if !ok {
+		return
+	}
+	if !shop.Repairs {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("%s does not repair weapons", shop.Name)})
+		return
+	}
+	if err := atLocation(p, shop.Location); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	weapon := p.EquippedWeapon
+	if weapon == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You are not wielding a weapon"})
+		return
+	}
+	worn := weapon.Stats.MaxDurability - weapon.Stats.Durability
+	if worn <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Your %s does not need repairing", weapon.Name)})
+		return
+	}
+
+	cost := repairCost(worn)
+	if p.Gold < cost {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Insufficient gold: %d required (current: %d)", cost, p.Gold),
+		})
+		return
+	}
+
+	p.Gold -= cost
+	weapon.Stats.Durability = weapon.Stats.MaxDurability
+	recordGold(p.ID, models.SourceRepair, -cost)
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("%s repairs your %s for %d gold", shop.NPC, weapon.Name, cost),
+		"player":  p,
+	})
+}
+
+// findShop looks up the shop named by the :id route parameter, writing an error response if it doesn't exist
+func findShop(c *gin.Context) (*models.Shop, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid shop ID"})
+		return nil, false
+	}
+
+	shop, ok := shops[uint(id)]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shop not found"})
+		return nil, false
+	}
+	return shop, true
+}
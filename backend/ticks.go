@@ -0,0 +1,125 @@
+package main
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"galycherrygame/backend/models"
+	"galycherrygame/backend/pkg/tick"
+	"galycherrygame/db"
+)
+
+const (
+	// defaultTickRate is how often game ticks run unless TICK_RATE overrides it
+	defaultTickRate = time.Second
+	// tickMaxCatchUp is how much missed time is replayed after a restart or
+	// when ticks fall behind
+	tickMaxCatchUp = time.Hour
+
+	// statusEffectInterval is how often damage and healing over time effects apply
+	statusEffectInterval = time.Second
+)
+
+// gameMu guards all game state, which is shared between request handlers and
+// the tick scheduler
+var gameMu sync.Mutex
+
+// newGameScheduler creates the tick scheduler with every time-based system registered
+func newGameScheduler(rate time.Duration, clock tick.Clock) (*tick.Scheduler, error) {
+	scheduler, err := tick.New(tick.Config{Rate: rate, MaxCatchUp: tickMaxCatchUp}, clock, tickStore{}, &gameMu)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduler.Register("travel", 0, func(t tick.Tick) {
+		for _, p := range players {
+			updateTravel(p, t.Time)
+		}
+	})
+	scheduler.Register("idle-actions", 0, func(t tick.Tick) {
+		processIdleQueues(t.Time)
+	})
+	scheduler.Register("status-effects", statusEffectInterval, func(t tick.Tick) {
+		for _, p := range players {
+			p.UpdateStatusEffects(t.Time)
+			checkPlayerDeath(p, "your wounds", nil)
+		}
+	})
+	scheduler.Register("regeneration", models.RegenerationInterval, func(t tick.Tick) {
+		for _, p := range players {
+			regenerate(p, t.Time)
+		}
+	})
+	scheduler.Register("farming", 0, func(t tick.Tick) {
+		rng := rand.New(rand.NewSource(t.Time.UnixNano()))
+		for _, plots := range farmPlots {
+			for _, plot := range plots {
+				if crop, ok := crops[plot.CropID]; ok {
+					plot.Update(crop, t.Time, rng)
+				}
+			}
+		}
+	})
+	scheduler.Register("shop-restock", 0, func(t tick.Tick) {
+		for _, shop := range shops {
+			shop.Restock(t.Time)
+		}
+	})
+	scheduler.Register("resource-respawn", 0, func(t tick.Tick) {
+		for _, node := range resourceNodes {
+			node.Update(t.Time)
+		}
+	})
+	scheduler.Register("trade-expiry", tradeExpiryInterval, func(t tick.Tick) {
+		expireTrades(t.Time)
+	})
+	scheduler.Register("gravestones", graveInterval, func(t tick.Tick) {
+		clearExpiredGravestones(t.Time)
+	})
+	scheduler.Register("achievements", achievementInterval, func(t tick.Tick) {
+		for _, p := range players {
+			checkAchievements(p)
+		}
+	})
+	scheduler.Register("leaderboards", leaderboardInterval, func(t tick.Tick) {
+		for _, p := range players {
+			updateLeaderboards(p, t.Time)
+		}
+	})
+	return scheduler, nil
+}
+
+// tickStore saves the last processed tick in the game_ticks table
+type tickStore struct{}
+
+// gameTick is the single row of the game_ticks table
+type gameTick struct {
+	Tick        int64
+	ProcessedAt time.Time
+}
+
+func (tickStore) Load() (tick.Tick, bool, error) {
+	if db.DB == nil {
+		return tick.Tick{}, false, nil
+	}
+
+	var row gameTick
+	result := db.DB.Raw("SELECT tick, processed_at FROM game_ticks WHERE id = 1").Scan(&row)
+	if result.Error != nil {
+		return tick.Tick{}, false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return tick.Tick{}, false, nil
+	}
+	return tick.Tick{Number: row.Tick, Time: row.ProcessedAt}, true, nil
+}
+
+func (tickStore) Save(t tick.Tick) error {
+	if db.DB == nil {
+		return nil
+	}
+	return db.DB.Exec(`INSERT INTO game_ticks (id, tick, processed_at) VALUES (1, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET tick = excluded.tick, processed_at = excluded.processed_at`,
+		t.Number, t.Time).Error
+}
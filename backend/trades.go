@@ -0,0 +1,278 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"galycherrygame/backend/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tradeExpiryInterval is how often trades that have timed out are closed
+const tradeExpiryInterval = 10 * time.Second
+
+// trades holds every trade session, keyed by trade ID
+var trades = map[uint]*models.Trade{}
+
+var nextTradeID uint = 1
+
+func getTrades(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	expireTrades(time.Now())
+
+	result := []*models.Trade{}
+	for _, trade := range trades {
+		if trade.Involves(p.ID) {
+			result = append(result, trade)
+		}
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func proposeTrade(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		PartnerID uint `json:"partnerId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	if request.PartnerID == p.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot trade with yourself"})
+		return
+	}
+	partner, ok := players[request.PartnerID]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Player not found"})
+		return
+	}
+
+	trade := models.NewTrade(nextTradeID, p.ID, partner.ID, time.Now())
+	trades[trade.ID] = trade
+	nextTradeID++
+
+	c.JSON(http.StatusCreated, trade)
+}
+
+func getTrade(c *gin.Context) {
+	trade, _, ok := findTrade(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, trade)
+}
+
+// offerInTrade moves items and gold from the player into escrow on their side of the trade
+func offerInTrade(c *gin.Context) {
+	trade, p, ok := findOpenTrade(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		ItemID   uint `json:"itemId"`
+		Quantity int  `json:"quantity" binding:"min=0"`
+		Gold     int  `json:"gold" binding:"min=0"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	if request.Gold > p.Gold {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("Insufficient gold: %d offered (current: %d)", request.Gold, p.Gold),
+		})
+		return
+	}
+
+	var item models.InventoryItem
+	if request.ItemID != 0 {
+		if request.Quantity < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Quantity must be at least 1"})
+			return
+		}
+		var found bool
+		item, found = takeFromInventory(p, request.ItemID, request.Quantity)
+		if !found {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "You do not have enough of that item"})
+			return
+		}
+	}
+
+	own, _ := trade.Offers(p.ID)
+	if request.ItemID != 0 {
+		own.AddItem(item)
+	}
+	p.Gold -= request.Gold
+	own.Gold += request.Gold
+	trade.Touch(time.Now())
+
+	c.JSON(http.StatusOK, gin.H{
+		"player": p,
+		"trade":  trade,
+	})
+}
+
+// confirmTrade records the player's agreement and swaps both offers once both sides have confirmed
+func confirmTrade(c *gin.Context) {
+	trade, p, ok := findOpenTrade(c)
+	if !ok {
+		return
+	}
+
+	own, _ := trade.Offers(p.ID)
+	own.Confirmed = true
+
+	if trade.BothConfirmed() {
+		initiator := players[trade.Initiator.PlayerID]
+		recipient := players[trade.Recipient.PlayerID]
+
+		// Check both sides can receive everything before moving anything
+		if !initiator.CanAddItems(trade.Recipient.Items) || !recipient.CanAddItems(trade.Initiator.Items) {
+			own.Confirmed = false
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Not enough inventory space to complete the trade"})
+			return
+		}
+
+		deliverOffer(trade.Initiator, recipient)
+		deliverOffer(trade.Recipient, initiator)
+		recordTradeOffer(trade.Initiator, recipient.ID)
+		recordTradeOffer(trade.Recipient, initiator.ID)
+		trade.Status = models.TradeCompleted
+		trade.UpdatedAt = time.Now()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"player": p,
+		"trade":  trade,
+	})
+}
+
+func cancelTrade(c *gin.Context) {
+	trade, p, ok := findOpenTrade(c)
+	if !ok {
+		return
+	}
+
+	if !closeTrade(trade, models.TradeCancelled) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Not enough inventory space to take back the items in the trade"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"player": p,
+		"trade":  trade,
+	})
+}
+
+// expireTrades closes every open trade that has timed out, returning escrow
+// to its owners. Trades whose items can't be returned yet stay open and are
+// tried again next time.
+func expireTrades(now time.Time) {
+	for _, trade := range trades {
+		if trade.IsExpired(now) {
+			closeTrade(trade, models.TradeExpired)
+		}
+	}
+}
+
+// closeTrade ends a trade without swapping, handing each offer back to the
+// player who made it. It reports false and leaves the trade open if either
+// player doesn't have room to take their items back.
+func closeTrade(trade *models.Trade, status string) bool {
+	initiator := players[trade.Initiator.PlayerID]
+	recipient := players[trade.Recipient.PlayerID]
+	if !initiator.CanAddItems(trade.Initiator.Items) || !recipient.CanAddItems(trade.Recipient.Items) {
+		return false
+	}
+
+	deliverOffer(trade.Initiator, initiator)
+	deliverOffer(trade.Recipient, recipient)
+	trade.Status = status
+	trade.UpdatedAt = time.Now()
+	return true
+}
+
+// deliverOffer releases an offer's escrowed gold and items to the given
+// player, who must have room for the items
+func deliverOffer(offer models.TradeOffer, to *models.Player) {
+	to.Gold += offer.Gold
+	for _, item := range offer.Items {
+		to.AddItemToInventory(item)
+	}
+}
+
+// recordTradeOffer logs an offer changing hands from the player who made it to the given player
+func recordTradeOffer(offer models.TradeOffer, toID uint) {
+	recordGold(offer.PlayerID, models.SourceTrade, -offer.Gold)
+	recordGold(toID, models.SourceTrade, offer.Gold)
+	for _, item := range offer.Items {
+		recordItem(offer.PlayerID, models.SourceTrade, item.ID, -item.Quantity)
+		recordItem(toID, models.SourceTrade, item.ID, item.Quantity)
+	}
+}
+
+// takeFromInventory removes a quantity of an item from the player, returning the removed stack
+func takeFromInventory(p *models.Player, itemID uint, quantity int) (models.InventoryItem, bool) {
+	for _, invItem := range p.Inventory.Materials {
+		if invItem.ID == itemID {
+			if !p.RemoveItemFromInventory(itemID, quantity) {
+				return models.InventoryItem{}, false
+			}
+			invItem.Quantity = quantity
+			return invItem, true
+		}
+	}
+	return models.InventoryItem{}, false
+}
+
+// findTrade looks up the trade named by the :id route parameter for the
+// current player, writing an error response if it can't be used
+func findTrade(c *gin.Context) (*models.Trade, *models.Player, bool) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return nil, nil, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid trade ID"})
+		return nil, nil, false
+	}
+
+	expireTrades(time.Now())
+
+	trade, ok := trades[uint(id)]
+	if !ok || !trade.Involves(p.ID) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Trade not found"})
+		return nil, nil, false
+	}
+	return trade, p, true
+}
+
+// findOpenTrade is findTrade for actions that need the trade to still be open
+func findOpenTrade(c *gin.Context) (*models.Trade, *models.Player, bool) {
+	trade, p, ok := findTrade(c)
+	if !ok {
+		return nil, nil, false
+	}
+	if trade.Status != models.TradeOpen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Trade is %s", trade.Status)})
+		return nil, nil, false
+	}
+	return trade, p, true
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"galycherrygame/backend/models"
+	"galycherrygame/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// startingLocation is where new players begin
+const startingLocation = "Cherry Village"
+
+// locations holds every place in the world, keyed by location ID
+var locations = map[uint]models.Location{
+	1: {ID: 1, Name: "Cherry Village", Description: "A sleepy village of farms and cherry orchards where every adventure begins"},
+	2: {ID: 2, Name: "Whispering Woods", Description: "A dark forest overrun by goblins and wolves"},
+	3: {ID: 3, Name: "Ironforge", Description: "A mining town built into the mountainside, loud with hammers"},
+	4: {ID: 4, Name: "Orc Badlands", Description: "Scorched plains beyond the mountains where the orc warbands roam"},
+}
+
+// routes connects locations in both directions
+var routes = []models.Route{
+	{From: 1, To: 2, Duration: 30 * time.Second},
+	{From: 2, To: 3, Duration: 45 * time.Second},
+	{From: 1, To: 3, Duration: 90 * time.Second},
+	{From: 3, To: 4, Duration: time.Minute},
+}
+
+// enemyLocations holds where each enemy can be fought, keyed by enemy name
+var enemyLocations = map[string]string{
+	"Goblin": "Whispering Woods",
+	"Wolf":   "Whispering Woods",
+	"Orc":    "Orc Badlands",
+}
+
+// questLocations holds where each quest is given out, keyed by quest name
+var questLocations = map[string]string{
+	"Goblin Slayer": "Cherry Village",
+	"Wolf Hunter":   "Whispering Woods",
+}
+
+// travels holds each player's journey in progress, keyed by player ID
+var travels = map[uint]*models.TravelAction{}
+
+// routesFrom returns every route leaving a location, ordered by destination ID
+func routesFrom(locationID uint) []models.Route {
+	from := []models.Route{}
+	for _, route := range routes {
+		if route.From == locationID {
+			from = append(from, route)
+		} else if route.To == locationID {
+			from = append(from, route.Reversed())
+		}
+	}
+	sort.Slice(from, func(i, j int) bool { return from[i].To < from[j].To })
+	return from
+}
+
+// locationByName returns the location with the given name
+func locationByName(name string) (models.Location, bool) {
+	for _, location := range locations {
+		if location.Name == name {
+			return location, true
+		}
+	}
+	return models.Location{}, false
+}
+
+// updateTravel moves the player to their destination once they have arrived
+func updateTravel(p *models.Player, now time.Time) {
+	travel, ok := travels[p.ID]
+	if !ok || !travel.HasArrived(now) {
+		return
+	}
+	p.Location = travel.To
+	delete(travels, p.ID)
+	savePlayerLocation(p)
+}
+
+// savePlayerLocation records where the player is standing in the players table
+func savePlayerLocation(p *models.Player) {
+	if db.DB == nil {
+		return
+	}
+	err := db.DB.Exec(`INSERT INTO players (id, name, location) VALUES (?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET location = excluded.location`,
+		p.ID, p.Name, p.Location).Error
+	if err != nil {
+		log.Printf("Failed to save the location of player %d: %v", p.ID, err)
+	}
+}
+
+// loadPlayerLocations puts each player back where they were standing when
+// the server stopped. Locations that no longer exist are ignored.
+func loadPlayerLocations() error {
+	if db.DB == nil {
+		return nil
+	}
+	for _, p := range players {
+		var location string
+		result := db.DB.Raw("SELECT location FROM players WHERE id = ?", p.ID).Scan(&location)
+		if result.Error != nil {
+			return result.Error
+		}
+		if _, ok := locationByName(location); result.RowsAffected > 0 && ok {
+			p.Location = location
+		}
+	}
+	return nil
+}
+
+// atLocation checks the player is standing in the given location and not on the road
+func atLocation(p *models.Player, location string) error {
+	updateTravel(p, time.Now())
+	if travel, ok := travels[p.ID]; ok {
+		return fmt.Errorf("you are travelling to %s", travel.To)
+	}
+	if p.Location != location {
+		return fmt.Errorf("you need to be in %s (you are in %s)", location, p.Location)
+	}
+	return nil
+}
+
+// canFight checks the player is where the enemy can be found
+func canFight(p *models.Player, enemyName string) error {
+	location, ok := enemyLocations[enemyName]
+	if !ok {
+		return fmt.Errorf("%s cannot be found anywhere", enemyName)
+	}
+	return atLocation(p, location)
+}
+
+// locationRoutes is a location with the routes leaving it
+type locationRoutes struct {
+	models.Location
+	Routes []models.Route `json:"routes"`
+}
+
+// locationContent is everything that can be found at a location
+type locationContent struct {
+	locationRoutes
+	Enemies       []models.Enemy           `json:"enemies"`
+	Shops         []*models.Shop           `json:"shops"`
+	Stations      []models.CraftingStation `json:"stations"`
+	FishingSpots  []*models.FishingSpot    `json:"fishingSpots"`
+	ResourceNodes []*models.ResourceNode   `json:"resourceNodes"`
+	Inns          []models.Inn             `json:"inns"`
+	NPCs          []*models.NPC            `json:"npcs"`
+	Quests        []string                 `json:"quests"`
+	Farm          bool                     `json:"farm"`
+}
+
+// contentAt gathers the enemies, shops, stations, fishing spots, resources, inns, NPCs and quests at a location
+func contentAt(location models.Location) locationContent {
+	content := locationContent{
+		locationRoutes: locationRoutes{Location: location, Routes: routesFrom(location.ID)},
+		Enemies:        []models.Enemy{},
+		Shops:          []*models.Shop{},
+		Stations:       []models.CraftingStation{},
+		FishingSpots:   []*models.FishingSpot{},
+		ResourceNodes:  []*models.ResourceNode{},
+		Inns:           []models.Inn{},
+		NPCs:           npcsAt(location.Name),
+		Quests:         []string{},
+		Farm:           location.Name == farmLocation,
+	}
+
+	for name, enemy := range enemyCatalog {
+		if enemyLocations[name] == location.Name {
+			content.Enemies = append(content.Enemies, enemy)
+		}
+	}
+	sort.Slice(content.Enemies, func(i, j int) bool { return content.Enemies[i].Level < content.Enemies[j].Level })
+
+	for _, shop := range shops {
+		if shop.Location == location.Name {
+			content.Shops = append(content.Shops, shop)
+		}
+	}
+	sort.Slice(content.Shops, func(i, j int) bool { return content.Shops[i].ID < content.Shops[j].ID })
+
+	for _, station := range cookingRanges {
+		if station.Location == location.Name {
+			content.Stations = append(content.Stations, station)
+		}
+	}
+	sort.Slice(content.Stations, func(i, j int) bool { return content.Stations[i].ID < content.Stations[j].ID })
+
+	for _, spot := range fishingSpots {
+		if spot.Location == location.Name {
+			content.FishingSpots = append(content.FishingSpots, spot)
+		}
+	}
+	sort.Slice(content.FishingSpots, func(i, j int) bool { return content.FishingSpots[i].ID < content.FishingSpots[j].ID })
+
+	for _, node := range resourceNodes {
+		if node.Location == location.Name {
+			content.ResourceNodes = append(content.ResourceNodes, node)
+		}
+	}
+	sort.Slice(content.ResourceNodes, func(i, j int) bool { return content.ResourceNodes[i].ID < content.ResourceNodes[j].ID })
+
+	for _, inn := range inns {
+		if inn.Location == location.Name {
+			content.Inns = append(content.Inns, inn)
+		}
+	}
+	sort.Slice(content.Inns, func(i, j int) bool { return content.Inns[i].ID < content.Inns[j].ID })
+
+	for _, quest := range availableQuests {
+		if questLocations[quest] == location.Name {
+			content.Quests = append(content.Quests, quest)
+		}
+	}
+	return content
+}
+
+// getLocations lists every location with the routes leaving it
+func getLocations(c *gin.Context) {
+	list := make([]locationRoutes, 0, len(locations))
+	for _, location := range locations {
+		list = append(list, locationRoutes{Location: location, Routes: routesFrom(location.ID)})
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	c.JSON(http.StatusOK, list)
+}
+
+// getLocation returns a location with everything that can be found there
+func getLocation(c *gin.Context) {
+	location, ok := findLocation(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, contentAt(location))
+}
+
+// getTravel returns where the player is and any journey in progress
+func getTravel(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	updateTravel(p, now)
+	response := gin.H{"location": p.Location}
+	if travel, ok := travels[p.ID]; ok {
+		response["travel"] = travel
+		response["remaining"] = travel.Remaining(now).String()
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// startTravel sets off along a route from the player's location. The player
+// arrives once the route's travel time has passed.
+func startTravel(c *gin.Context) {
+	p, ok := currentPlayer(c)
+	if !ok {
+		return
+	}
+
+	var request struct {
+		LocationID uint `json:"locationId" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&request); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": errorMessage(err)})
+		return
+	}
+
+	destination, ok := locations[request.LocationID]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Location not found"})
+		return
+	}
+
+	now := time.Now()
+	updateTravel(p, now)
+	if travel, ok := travels[p.ID]; ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("You are already travelling to %s", travel.To)})
+		return
+	}
+	if current, ok := gatheringActions[p.ID]; ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("You are busy %s at %s", gatheringVerb(current.Skill), current.Source),
+		})
+		return
+	}
+	if len(idleQueues[p.ID]) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Cancel your idle actions before travelling"})
+		return
+	}
+	if run, ok := dungeonRuns[p.ID]; ok && run.Status == models.RunActive {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Finish or flee %s before travelling", run.DungeonName)})
+		return
+	}
+	if inCombat(p, now) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "You cannot travel while in combat"})
+		return
+	}
+
+	origin, ok := locationByName(p.Location)
+	if !ok {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Your current location is unknown"})
+		return
+	}
+	if origin.ID == destination.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("You are already in %s", destination.Name)})
+		return
+	}
+
+	var route *models.Route
+	for _, r := range routesFrom(origin.ID) {
+		if r.To == destination.ID {
+			route = &r
+			break
+		}
+	}
+	if route == nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": fmt.Sprintf("There is no route from %s to %s", origin.Name, destination.Name),
+		})
+		return
+	}
+
+	travel := &models.TravelAction{
+		PlayerID:  p.ID,
+		From:      origin.Name,
+		To:        destination.Name,
+		StartedAt: now,
+		Duration:  route.Duration,
+	}
+	travels[p.ID] = travel
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": fmt.Sprintf("You set off from %s to %s", origin.Name, destination.Name),
+		"player":  p,
+		"travel":  travel,
+	})
+}
+
+// findLocation looks up the location named by the :id route parameter, writing an error response if it doesn't exist
+func findLocation(c *gin.Context) (models.Location, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid location ID"})
+		return models.Location{}, false
+	}
+
+	location, ok := locations[uint(id)]
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Location not found"})
+		return models.Location{}, false
+	}
+	return location, true
+}
@@ -25,6 +25,9 @@ func Migrate(db *gorm.DB) error {
 		"006_add_crafting_station_fields.sql",
 		"007_add_combat_stats.sql",
 		"008_add_combat_abilities.sql",
+		"011_add_game_ticks.sql",
+		"014_add_player_location.sql",
+		"015_add_player_deaths.sql",
 	}
 
 	for _, migration := range migrations {
@@ -0,0 +1,5 @@
+CREATE TABLE game_ticks (
+    id INTEGER PRIMARY KEY CHECK (id = 1),
+    tick INTEGER NOT NULL,
+    processed_at DATETIME NOT NULL
+);
@@ -0,0 +1 @@
+ALTER TABLE players ADD COLUMN location TEXT NOT NULL DEFAULT 'Cherry Village';
@@ -0,0 +1,13 @@
+ALTER TABLE players ADD COLUMN home TEXT NOT NULL DEFAULT 'Cherry Village';
+
+CREATE TABLE death_records (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    player_id INTEGER NOT NULL,
+    cause TEXT NOT NULL,
+    location TEXT NOT NULL,
+    gold_lost INTEGER NOT NULL DEFAULT 0,
+    items_dropped INTEGER NOT NULL DEFAULT 0,
+    died_at DATETIME NOT NULL,
+    FOREIGN KEY(player_id) REFERENCES players(id)
+);
+CREATE INDEX idx_death_records_player ON death_records (player_id, died_at DESC);